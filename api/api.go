@@ -38,7 +38,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"path"
+	"sort"
 	"strings"
 	"time"
 
@@ -50,6 +50,7 @@ import (
 	"github.com/wcsanders1/MockApiHub/wrapper"
 
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
 )
 
 type (
@@ -61,19 +62,31 @@ type (
 		GetPort() int
 		GetBaseURL() string
 		GetEndpoints() map[string]config.Endpoint
+		GetResponseSchemaViolations() map[string][]string
+		GetReceivedFrames() map[string][]ReceivedFrame
+		GetProtocol() string
 	}
 
 	// API contains information for an API.
 	API struct {
-		baseURL    string
-		endpoints  map[string]config.Endpoint
-		server     wrapper.IServerOps
-		handlers   map[string]map[string]func(http.ResponseWriter, *http.Request)
-		routeTree  route.ITree
-		httpConfig config.HTTP
-		log        *logrus.Entry
-		file       wrapper.IFileOps
-		creator    iCreator
+		baseURL             string
+		endpoints           map[string]config.Endpoint
+		server              wrapper.IServerOps
+		handlers            map[string]map[string]func(http.ResponseWriter, *http.Request)
+		matchedHandlers     map[string]map[string][]matchedHandler
+		responseValidators  map[string][]*responseValidator
+		frameJournals       map[string]*frameJournal
+		routeTree           route.ITree
+		httpConfig          config.HTTP
+		log                 *logrus.Entry
+		file                wrapper.IFileOps
+		creator             iCreator
+		caseSensitive       bool
+		strictTrailingSlash bool
+		virtualHost         string
+		protocol            string
+		descriptorSet       string
+		grpcServer          *grpc.Server
 	}
 )
 
@@ -81,14 +94,22 @@ type (
 func NewAPI(config *config.APIConfig) (*API, error) {
 	api := &API{}
 	api.log = log.NewLogger(&config.Log, "api").WithFields(logrus.Fields{
-		log.BaseURLField:  config.BaseURL,
-		log.PortField:     config.HTTP.Port,
-		log.UseTLSField:   config.HTTP.UseTLS,
-		log.CertFileField: config.HTTP.CertFile,
-		log.KeyFileField:  config.HTTP.KeyFile,
+		log.BaseURLField:     config.BaseURL,
+		log.VirtualHostField: config.VirtualHost,
+		log.PortField:        config.HTTP.Port,
+		log.UseTLSField:      config.HTTP.UseTLS,
+		log.CertFileField:    config.HTTP.CertFile,
+		log.KeyFileField:     config.HTTP.KeyFile,
 	})
 	contextLogger := api.log.WithField(log.FuncField, ref.GetFuncName())
 
+	if strings.EqualFold(config.Protocol, ProtocolGRPC) && config.HTTP.Shared {
+		err := errors.New("gRPC mock APIs cannot share a port")
+		contextLogger.WithError(err).Error("error creating mock API")
+		return nil, err
+	}
+
+	api.protocol = config.Protocol
 	server, err := createAPIServer(&config.HTTP, api)
 	if err != nil {
 		contextLogger.WithError(err).Error("error creating mock API")
@@ -99,16 +120,21 @@ func NewAPI(config *config.APIConfig) (*API, error) {
 	api.baseURL = config.BaseURL
 	api.endpoints = config.Endpoints
 	api.handlers = make(map[string]map[string]func(http.ResponseWriter, *http.Request))
-	api.routeTree = route.NewRouteTree()
+	api.routeTree = route.NewRouteTreeWithOptions(config.CaseSensitive, config.StrictTrailingSlash)
 	api.httpConfig = config.HTTP
 	api.file = &wrapper.FileOps{}
 	api.creator = newCreator(api.log)
+	api.caseSensitive = config.CaseSensitive
+	api.strictTrailingSlash = config.StrictTrailingSlash
+	api.virtualHost = config.VirtualHost
+	api.descriptorSet = config.DescriptorSet
 
 	contextLogger.Info("successfully created mock API")
 	return api, nil
 }
 
-// Start starts an api server.
+// Start starts an api server, returning once it is listening. Files named by the endpoints are read
+// from dir.
 func (api *API) Start(dir, defaultCert, defaultKey string) error {
 	contextLogger := api.log.WithFields(logrus.Fields{
 		log.FuncField:            ref.GetFuncName(),
@@ -118,14 +144,23 @@ func (api *API) Start(dir, defaultCert, defaultKey string) error {
 	})
 	contextLogger.Debug("starting API")
 
-	api.handlers["OPTIONS"] = make(map[string]func(http.ResponseWriter, *http.Request))
-	for endpointName, endpoint := range api.endpoints {
-		var path string
-		if len(api.baseURL) > 0 {
-			path = fmt.Sprintf("%s/%s", api.baseURL, endpoint.Path)
-		} else {
-			path = endpoint.Path
+	if api.isGRPC() {
+		if err := api.registerGRPCMethods(dir); err != nil {
+			contextLogger.WithError(err).Error("error registering gRPC methods")
+			return err
 		}
+		return api.listenAndServe(defaultCert, defaultKey, contextLogger)
+	}
+
+	api.handlers["OPTIONS"] = make(map[string]func(http.ResponseWriter, *http.Request))
+	api.matchedHandlers = make(map[string]map[string][]matchedHandler)
+	api.responseValidators = make(map[string][]*responseValidator)
+	api.frameJournals = make(map[string]*frameJournal)
+	// Endpoints are registered in order of name, so that of two endpoints handling the same
+	// requests, the one whose name sorts later is always the one dropped.
+	for _, endpointName := range getSortedEndpointNames(api.endpoints) {
+		endpoint := api.endpoints[endpointName]
+		path := getEndpointPath(api.baseURL, endpoint)
 		registeredRoute := api.ensureRouteRegistered(path)
 		file := endpoint.File
 		method := strings.ToUpper(endpoint.Method)
@@ -139,18 +174,40 @@ func (api *API) Start(dir, defaultCert, defaultKey string) error {
 		})
 
 		contextLoggerEndpoint.Debug("registering endpoint")
-		if route, methodExists := api.handlers[method]; !methodExists {
-			api.handlers[method] = make(map[string]func(http.ResponseWriter, *http.Request))
-		} else {
-			if _, routeExists := route[registeredRoute]; routeExists {
-				contextLoggerEndpoint.Warn("endpoint already exists, moving on to next endpoint...")
-				delete(api.endpoints, endpointName)
-				continue
-			}
+		matcher, err := newRequestMatcher(endpoint)
+		if err != nil {
+			contextLoggerEndpoint.WithError(err).Error("invalid query or request header matcher, moving on to next endpoint...")
+			delete(api.endpoints, endpointName)
+			continue
+		}
+
+		if api.matchedHandlerExists(method, registeredRoute, matcher) {
+			contextLoggerEndpoint.Warn("endpoint already exists, moving on to next endpoint...")
+			delete(api.endpoints, endpointName)
+			continue
 		}
 
 		contextLoggerEndpoint.Debug("registered endpoint; now assigning handler")
-		api.handlers[method][registeredRoute] = api.creator.getHandler(endpoint.EnforceValidJSON, endpoint.AllowCORS, endpoint.HTTPStatusCode, endpoint.Headers, dir, file, api.file)
+		handler := api.creator.getHandler(endpoint, dir, api.file)
+		if len(endpoint.ResponseSchema) > 0 {
+			validators := getResponseValidators(endpoint, dir, api.file, contextLoggerEndpoint)
+			for _, validator := range validators {
+				validator.validateIfChanged()
+				handler = validator.wrap(handler)
+			}
+			api.responseValidators[endpointName] = validators
+		}
+		if strings.EqualFold(endpoint.Type, EndpointTypeWebSocket) {
+			journal := &frameJournal{}
+			api.frameJournals[endpointName] = journal
+			handler = journal.wrap(handler)
+		}
+
+		api.addMatchedHandler(method, registeredRoute, matchedHandler{
+			endpointName: endpointName,
+			matcher:      matcher,
+			handler:      handler,
+		})
 		if endpoint.AllowCORS {
 			api.handlers["OPTIONS"][registeredRoute] = func(w http.ResponseWriter, r *http.Request) {
 				w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -160,6 +217,27 @@ func (api *API) Start(dir, defaultCert, defaultKey string) error {
 		}
 	}
 
+	return api.listenAndServe(defaultCert, defaultKey, contextLogger)
+}
+
+func (api *API) listenAndServe(defaultCert, defaultKey string, contextLogger *logrus.Entry) error {
+	if api.httpConfig.Shared {
+		contextLogger.Debug("mock API uses a shared server, which will listen for it")
+		return nil
+	}
+
+	port, err := api.server.Listen()
+	if err != nil {
+		contextLogger.WithError(err).Error("error listening")
+		return err
+	}
+
+	if api.httpConfig.Port == 0 {
+		api.httpConfig.Port = config.Port(port)
+		api.log = api.log.WithField(log.PortField, port)
+		contextLogger.WithField(log.PortField, port).Info("listening on a free port")
+	}
+
 	return api.creator.startAPI(defaultCert, defaultKey, api.server, api.httpConfig)
 }
 
@@ -169,6 +247,11 @@ func (api *API) Shutdown() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	if api.grpcServer != nil {
+		// Stopping the gRPC server ends the calls in progress, which would otherwise hold up the shutdown.
+		api.grpcServer.Stop()
+	}
+
 	if err := api.server.Shutdown(ctx); err != nil {
 		contextLogger.WithError(err).Error("error shutting down mock API")
 		return err
@@ -179,7 +262,12 @@ func (api *API) Shutdown() error {
 }
 
 func (api *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	path, params, err := api.routeTree.GetRoute(str.CleanURL(r.URL.Path))
+	if api.grpcServer != nil {
+		api.grpcServer.ServeHTTP(w, r)
+		return
+	}
+
+	path, params, err := api.routeTree.GetRoute(str.CleanURLWithOptions(r.URL.Path, api.caseSensitive, api.strictTrailingSlash))
 	contextLogger := api.log.WithFields(logrus.Fields{
 		log.FuncField: ref.GetFuncName(),
 		log.PathField: path,
@@ -213,9 +301,10 @@ func (api *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("endpoint not found"))
 }
 
-// GetPort returns the API's port number.
+// GetPort returns the API's port number. If the API was configured to use a free port, this is
+// the port chosen once the API has started.
 func (api *API) GetPort() int {
-	return api.httpConfig.Port
+	return int(api.httpConfig.Port)
 }
 
 // GetBaseURL returns the API's base URL.
@@ -228,25 +317,140 @@ func (api *API) GetEndpoints() map[string]config.Endpoint {
 	return api.endpoints
 }
 
+// GetResponseSchemaViolations returns, by endpoint name, the ways in which the files the API's
+// endpoints serve violate their response schemas.
+func (api *API) GetResponseSchemaViolations() map[string][]string {
+	violations := make(map[string][]string)
+	for endpointName, validators := range api.responseValidators {
+		var endpointViolations []string
+		for _, validator := range validators {
+			endpointViolations = append(endpointViolations, validator.getViolations()...)
+		}
+		if len(endpointViolations) > 0 {
+			violations[endpointName] = endpointViolations
+		}
+	}
+	return violations
+}
+
+// GetReceivedFrames returns, by endpoint name, the frames clients have most recently sent to the
+// API's WebSocket endpoints, oldest first.
+func (api *API) GetReceivedFrames() map[string][]ReceivedFrame {
+	frames := make(map[string][]ReceivedFrame)
+	for endpointName, journal := range api.frameJournals {
+		frames[endpointName] = journal.getFrames()
+	}
+	return frames
+}
+
+// GetProtocol returns the protocol the API serves, which is empty for HTTP.
+func (api *API) GetProtocol() string {
+	return api.protocol
+}
+
+func getSortedEndpointNames(endpoints map[string]config.Endpoint) []string {
+	names := make([]string, 0, len(endpoints))
+	for endpointName := range endpoints {
+		names = append(names, endpointName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (api *API) isGRPC() bool {
+	return strings.EqualFold(api.protocol, ProtocolGRPC)
+}
+
 func (api *API) ensureRouteRegistered(url string) string {
-	url = path.Clean(url)
-	registeredRoute, _, _ := api.routeTree.GetRoute(url)
+	return ensureRouteRegistered(api.routeTree, url, api.strictTrailingSlash)
+}
+
+func ensureRouteRegistered(routeTree route.ITree, url string, strictTrailingSlash bool) string {
+	url = str.CleanPath(url, strictTrailingSlash)
+	registeredRoute, _, _ := routeTree.GetRoute(url)
 	if len(registeredRoute) == 0 {
-		registeredRoute, _ = api.routeTree.AddRoute(url)
+		registeredRoute, _ = routeTree.AddRoute(url)
 	}
 
 	return registeredRoute
 }
 
+func getEndpointPath(baseURL string, endpoint config.Endpoint) string {
+	if len(baseURL) == 0 {
+		return endpoint.Path
+	}
+	if len(endpoint.Path) == 0 {
+		return baseURL
+	}
+	return fmt.Sprintf("%s/%s", baseURL, endpoint.Path)
+}
+
+func (api *API) matchedHandlerExists(method, route string, matcher *requestMatcher) bool {
+	for _, existing := range api.matchedHandlers[method][route] {
+		if existing.matcher.hasSameConditions(matcher) {
+			return true
+		}
+	}
+	return false
+}
+
+func (api *API) addMatchedHandler(method, route string, handler matchedHandler) {
+	if _, exists := api.handlers[method]; !exists {
+		api.handlers[method] = make(map[string]func(http.ResponseWriter, *http.Request))
+	}
+	if _, exists := api.matchedHandlers[method]; !exists {
+		api.matchedHandlers[method] = make(map[string][]matchedHandler)
+	}
+
+	handlers := append(api.matchedHandlers[method][route], handler)
+	sort.SliceStable(handlers, func(i, j int) bool {
+		if handlers[i].matcher.specificity() != handlers[j].matcher.specificity() {
+			return handlers[i].matcher.specificity() > handlers[j].matcher.specificity()
+		}
+		return handlers[i].endpointName < handlers[j].endpointName
+	})
+
+	api.matchedHandlers[method][route] = handlers
+	api.handlers[method][route] = api.getMatchingHandler(method, route)
+}
+
+func (api *API) getMatchingHandler(method, route string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, matched := range api.matchedHandlers[method][route] {
+			if matched.matcher.matches(r) {
+				api.log.WithField(log.EndpointNameField, matched.endpointName).Debug("endpoint matches request")
+				matched.handler(w, r)
+				return
+			}
+		}
+
+		api.log.WithFields(logrus.Fields{
+			log.MethodField: method,
+			log.RouteField:  route,
+		}).Warn("no endpoint matches the query string and headers of the request")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("no endpoint matches the request"))
+	}
+}
+
 func createAPIServer(config *config.HTTP, api *API) (*http.Server, error) {
-	if config.Port == 0 {
-		return nil, errors.New("no port provided")
+	if config.Port < 0 {
+		return nil, errors.New("invalid port provided")
 	}
 
 	server := &http.Server{
-		Addr:    str.GetPort(config.Port),
+		Addr:    str.GetPort(int(config.Port)),
 		Handler: api,
 	}
+	if api.isGRPC() {
+		// gRPC clients use HTTP/2, which, without TLS, they speak from the start of the connection.
+		// The standard library serves it directly; golang.org/x/net/http2/h2c is deprecated in its
+		// favor, and the gRPC module already requires a Go version that has it.
+		server.Protocols = new(http.Protocols)
+		server.Protocols.SetHTTP1(true)
+		server.Protocols.SetHTTP2(true)
+		server.Protocols.SetUnencryptedHTTP2(true)
+	}
 
 	return server, nil
 }
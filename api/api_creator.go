@@ -1,29 +1,53 @@
 package api
 
 import (
+	"bytes"
+	"crypto/sha256"
+	encodingJSON "encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/wcsanders1/MockApiHub/config"
-	"github.com/wcsanders1/MockApiHub/constants"
 	"github.com/wcsanders1/MockApiHub/json"
 	"github.com/wcsanders1/MockApiHub/log"
 	"github.com/wcsanders1/MockApiHub/ref"
+	"github.com/wcsanders1/MockApiHub/schema"
 	"github.com/wcsanders1/MockApiHub/wrapper"
+	"github.com/wcsanders1/MockApiHub/xml"
 
 	"github.com/sirupsen/logrus"
 )
 
 type (
 	iCreator interface {
-		getHandler(enforceValidJSON, allowCORS bool, statusCode int, headers []config.Header, dir, fileName string, file wrapper.IFileOps) func(w http.ResponseWriter, r *http.Request)
+		getHandler(endpoint config.Endpoint, dir string, file wrapper.IFileOps) func(w http.ResponseWriter, r *http.Request)
 		startAPI(defaultCert, defaultKey string, server wrapper.IServerOps, httpConfig config.HTTP) error
 	}
 
 	creator struct {
 		log *logrus.Entry
 	}
+
+	// envFileOps expands environment variables in the files it reads, as config.ExpandEnv does.
+	envFileOps struct {
+		wrapper.IFileOps
+	}
+
+	// fileETag keeps the entity tag of a file streamed from the disk until the file's modification
+	// time or size changes, so that the file is not hashed on every request.
+	fileETag struct {
+		mutex   sync.Mutex
+		modTime time.Time
+		size    int64
+		etag    string
+	}
 )
 
 func newCreator(logger *logrus.Entry) *creator {
@@ -32,29 +56,204 @@ func newCreator(logger *logrus.Entry) *creator {
 	}
 }
 
-func (c creator) getHandler(enforceValidJSON, allowCORS bool, statusCode int, headers []config.Header, dir, fileName string, file wrapper.IFileOps) func(w http.ResponseWriter, r *http.Request) {
-	var path string
-	if len(fileName) > 0 {
-		path = fmt.Sprintf("%s/%s/%s", constants.APIDir, dir, fileName)
-	} else {
-		path = ""
+func (c creator) getHandler(endpoint config.Endpoint, dir string, file wrapper.IFileOps) func(w http.ResponseWriter, r *http.Request) {
+	var handler func(w http.ResponseWriter, r *http.Request)
+	switch {
+	case strings.EqualFold(endpoint.Type, EndpointTypeWebSocket):
+		// WebSocket connections take over the request's connection, so they are not compressed.
+		return c.getWebSocketHandler(endpoint)
+	case strings.EqualFold(endpoint.Type, EndpointTypeSSE):
+		handler = c.getSSEHandler(endpoint, dir, file)
+	case strings.EqualFold(endpoint.Type, EndpointTypeGraphQL):
+		handler = c.getGraphQLHandler(endpoint, dir, file)
+	case len(endpoint.Sequence) > 0:
+		handler = c.getSequenceHandler(endpoint, dir, file)
+	default:
+		handler = c.getResponseHandler(endpoint, dir, file)
 	}
+	handler = getCompressionHandler(endpoint.Compression, handler, c.log)
+
+	if len(endpoint.RequestSchema) > 0 {
+		schemaPath := getFilePath(dir, endpoint.RequestSchema)
+		contextLogger := c.log.WithFields(logrus.Fields{
+			log.FuncField:   "handler for mock API",
+			log.SchemaField: schemaPath,
+		})
+		return getRequestValidationHandler(schemaPath, endpoint.RequestSchemaStatusCode, handler, file, contextLogger)
+	}
+	return handler
+}
 
+func (c creator) getResponseHandler(endpoint config.Endpoint, dir string, file wrapper.IFileOps) func(w http.ResponseWriter, r *http.Request) {
+	path := getFilePath(dir, endpoint.File)
 	contextLogger := c.log.WithFields(logrus.Fields{
 		log.FuncField:      "handler for mock API",
-		"enforceValidJSON": enforceValidJSON,
+		"enforceValidJSON": endpoint.EnforceValidJSON,
+		"enforceValidXML":  endpoint.EnforceValidXML,
 		log.PathField:      path,
 	})
 
-	if enforceValidJSON {
-		return getJSONHandler(path, headers, file, contextLogger, allowCORS, statusCode)
+	if endpoint.ExpandEnv {
+		file = &envFileOps{file}
+	}
+
+	if len(path) > 0 && (len(endpoint.Body) > 0 || endpoint.JSON != nil) {
+		contextLogger.Warn("endpoint has both a file and an inline body -- serving the file")
+	}
+
+	if len(path) == 0 && endpoint.JSON != nil {
+		body, err := encodingJSON.Marshal(endpoint.JSON)
+		if err != nil {
+			contextLogger.WithError(err).Error("error rendering inline JSON body")
+			return func(w http.ResponseWriter, r *http.Request) {
+				writeError(err, w)
+			}
+		}
+		headers := withContentType(endpoint.Headers, endpoint.ContentType, jsonContentType)
+		return getBodyHandler(body, nil, headers, contextLogger, endpoint.AllowCORS, endpoint.HTTPStatusCode)
+	}
+
+	if len(path) == 0 && len(endpoint.Body) > 0 {
+		headers := withContentType(endpoint.Headers, endpoint.ContentType, "")
+		return getBodyHandler([]byte(endpoint.Body), getBodyValidator(endpoint), headers, contextLogger, endpoint.AllowCORS, endpoint.HTTPStatusCode)
 	}
-	return getGeneralHandler(path, headers, file, contextLogger, allowCORS, statusCode)
+
+	contentType := getContentType(path)
+	if endpoint.EnforceValidJSON && len(contentType) == 0 {
+		contentType = jsonContentType
+	}
+	if endpoint.EnforceValidXML && len(contentType) == 0 {
+		contentType = xmlContentType
+	}
+	headers := withContentType(endpoint.Headers, endpoint.ContentType, contentType)
+	cacheHeaders := !endpoint.DisableCacheHeaders
+
+	var handler func(w http.ResponseWriter, r *http.Request)
+	switch {
+	case endpoint.CacheFile && len(path) > 0:
+		cache := newFileCache(path, file, getBodyValidator(endpoint), contextLogger)
+		handler = getCachedHandler(cache, headers, contextLogger, endpoint.AllowCORS, endpoint.HTTPStatusCode, cacheHeaders)
+	case endpoint.EnforceValidJSON || endpoint.EnforceValidXML:
+		handler = getValidatedHandler(path, getBodyValidator(endpoint), headers, file, contextLogger, endpoint.AllowCORS, endpoint.HTTPStatusCode, cacheHeaders)
+	default:
+		handler = getGeneralHandler(path, headers, file, contextLogger, endpoint.AllowCORS, endpoint.HTTPStatusCode, cacheHeaders)
+	}
+
+	if strings.EqualFold(endpoint.Compression, CompressionPrecompressed) && len(path) > 0 {
+		return getPrecompressedHandler(path, headers, file, contextLogger, endpoint.AllowCORS, endpoint.HTTPStatusCode, cacheHeaders, handler)
+	}
+	return handler
 }
 
-func getJSONHandler(path string, headers []config.Header, file wrapper.IFileOps, logger *logrus.Entry, allowCORS bool, statusCode int) func(w http.ResponseWriter, r *http.Request) {
+// getSequenceHandler returns a handler that serves the endpoint's responses in turn, repeating the
+// last response once the others have been served.
+func (c creator) getSequenceHandler(endpoint config.Endpoint, dir string, file wrapper.IFileOps) func(w http.ResponseWriter, r *http.Request) {
+	handlers := make([]func(w http.ResponseWriter, r *http.Request), len(endpoint.Sequence))
+	for i, response := range endpoint.Sequence {
+		step := endpoint
+		step.Sequence = nil
+		step.File = response.File
+		step.Body = response.Body
+		step.JSON = response.JSON
+		step.Headers = append(append([]config.Header{}, endpoint.Headers...), response.Headers...)
+		if response.HTTPStatusCode > 0 {
+			step.HTTPStatusCode = response.HTTPStatusCode
+		}
+		handlers[i] = c.getResponseHandler(step, dir, file)
+	}
+
+	var mutex sync.Mutex
+	next := 0
 	return func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		handler := handlers[next]
+		if next < len(handlers)-1 {
+			next++
+		}
+		mutex.Unlock()
+
+		handler(w, r)
+	}
+}
+
+// ReadAll reads a file to its end, expanding environment variables in its contents.
+func (ops *envFileOps) ReadAll(file *os.File) ([]byte, error) {
+	bytes, err := ops.IFileOps.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(config.ExpandEnv(string(bytes))), nil
+}
+
+func getFilePath(dir, fileName string) string {
+	if len(fileName) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", dir, fileName)
+}
+
+func readFile(path string, file wrapper.IFileOps) ([]byte, error) {
+	f, err := file.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return file.ReadAll(f)
+}
+
+// getBodyValidator returns the function that checks the bodies an endpoint serves, if it enforces
+// valid JSON or XML, or nil if it does not.
+func getBodyValidator(endpoint config.Endpoint) func([]byte) error {
+	switch {
+	case endpoint.EnforceValidJSON:
+		return json.ValidateJSON
+	case endpoint.EnforceValidXML:
+		return xml.ValidateXML
+	}
+	return nil
+}
+
+func getRequestValidationHandler(schemaPath string, statusCode int, next func(w http.ResponseWriter, r *http.Request), file wrapper.IFileOps, logger *logrus.Entry) func(w http.ResponseWriter, r *http.Request) {
+	if statusCode < 400 || statusCode > 499 {
+		statusCode = http.StatusBadRequest
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body []byte
+		if r.Body != nil {
+			var err error
+			body, err = ioutil.ReadAll(r.Body)
+			if err != nil {
+				logger.WithError(err).Error("error reading request body")
+				writeError(err, w)
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		violations, err := schema.Validate(schemaPath, body, file)
+		if err != nil {
+			logger.WithError(err).Error("error validating request body against schema")
+			writeError(err, w)
+			return
+		}
+
+		if len(violations) > 0 {
+			logger.WithField(log.ViolationsField, violations).Warn("request body does not match schema")
+			writeViolations("request body does not match schema", violations, statusCode, w)
+			return
+		}
+
+		logger.Debug("request body matches schema")
+		next(w, r)
+	}
+}
 
+// getValidatedHandler returns a handler that serves a file only if validate accepts its contents.
+func getValidatedHandler(path string, validate func([]byte) error, headers []config.Header, file wrapper.IFileOps, logger *logrus.Entry, allowCORS bool, statusCode int, cacheHeaders bool) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
 		for _, header := range headers {
 			w.Header().Set(header.Key, header.Value)
 		}
@@ -63,27 +262,41 @@ func getJSONHandler(path string, headers []config.Header, file wrapper.IFileOps,
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 		}
 
-		if statusCode > 0 && len(http.StatusText(statusCode)) > 0 {
-
-			w.WriteHeader(statusCode)
-		}
-
 		if len(path) == 0 {
+			writeStatusCode(statusCode, w)
 			return
 		}
 
-		bytes, err := json.GetJSON(path, file)
+		content, err := readFile(path, file)
+		if err == nil {
+			err = validate(content)
+		}
 		if err != nil {
-			logger.WithError(err).Error("error serving JSON from this endpoint")
+			logger.WithError(err).Error("error serving validated file from this endpoint")
 			writeError(err, w)
 			return
 		}
-		logger.Debug("successfully retrieved JSON; serving it")
-		w.Write(bytes)
+		logger.Debug("successfully retrieved and validated file; serving it")
+		var modTime time.Time
+		var etag string
+		if cacheHeaders {
+			etag = getETag(content)
+			// The contents of a file whose environment variables are expanded can change without the
+			// file changing, so they have only an entity tag.
+			if _, transforms := file.(*envFileOps); !transforms {
+				modTime = getModTime(path, file)
+			}
+		}
+		serveContent(w, r, path, modTime, etag, bytes.NewReader(content), statusCode)
 	}
 }
 
-func getGeneralHandler(path string, headers []config.Header, file wrapper.IFileOps, logger *logrus.Entry, allowCORS bool, statusCode int) func(w http.ResponseWriter, r *http.Request) {
+func getBodyHandler(body []byte, validate func([]byte) error, headers []config.Header, logger *logrus.Entry, allowCORS bool, statusCode int) func(w http.ResponseWriter, r *http.Request) {
+	var err error
+	if validate != nil {
+		err = validate(body)
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		for _, header := range headers {
 			w.Header().Set(header.Key, header.Value)
@@ -93,31 +306,133 @@ func getGeneralHandler(path string, headers []config.Header, file wrapper.IFileO
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 		}
 
+		if err != nil {
+			logger.WithError(err).Error("error serving inline body from this endpoint")
+			writeError(err, w)
+			return
+		}
+
 		if statusCode > 0 && len(http.StatusText(statusCode)) > 0 {
 			w.WriteHeader(statusCode)
 		}
 
+		logger.Debug("successfully serving inline body")
+		w.Write(body)
+	}
+}
+
+// getGeneralHandler returns a handler that serves a file as it is read from the disk, so large files
+// are not held in memory, unless the file operations transform the files they read.
+func getGeneralHandler(path string, headers []config.Header, file wrapper.IFileOps, logger *logrus.Entry, allowCORS bool, statusCode int, cacheHeaders bool) func(w http.ResponseWriter, r *http.Request) {
+	var fileETag fileETag
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, header := range headers {
+			w.Header().Set(header.Key, header.Value)
+		}
+
+		if allowCORS {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		}
+
 		if len(path) == 0 {
+			writeStatusCode(statusCode, w)
 			return
 		}
 
-		fileInfo, err := file.Open(path)
-		defer fileInfo.Close()
+		f, err := file.Open(path)
 		if err != nil {
 			logger.WithError(err).Error("error opening file")
 			writeError(err, w)
 			return
 		}
-
-		bytes, err := file.ReadAll(fileInfo)
-		if err != nil {
-			logger.WithError(err).Error("error reading file")
-			writeError(err, w)
-			return
+		defer f.Close()
+
+		var content io.ReadSeeker = f
+		var modTime time.Time
+		var etag string
+		if _, transforms := file.(*envFileOps); transforms {
+			data, err := file.ReadAll(f)
+			if err != nil {
+				logger.WithError(err).Error("error reading file")
+				writeError(err, w)
+				return
+			}
+			content = bytes.NewReader(data)
+			if cacheHeaders {
+				etag = getETag(data)
+			}
+		} else if cacheHeaders {
+			if info, err := f.Stat(); err == nil {
+				modTime = info.ModTime()
+				if etag, err = fileETag.get(f, info); err != nil {
+					logger.WithError(err).Error("error hashing file")
+					writeError(err, w)
+					return
+				}
+			}
 		}
 
 		logger.Debug("successfully serving data")
-		w.Write(bytes)
+		serveContent(w, r, path, modTime, etag, content, statusCode)
+	}
+}
+
+// serveContent writes content, honoring Range and conditional requests, unless a status code other
+// than 200 is configured, in which case it writes the whole of content with that status code. A zero
+// modTime or an empty etag leaves out the Last-Modified or ETag header.
+func serveContent(w http.ResponseWriter, r *http.Request, name string, modTime time.Time, etag string, content io.ReadSeeker, statusCode int) {
+	if statusCode > 0 && statusCode != http.StatusOK && len(http.StatusText(statusCode)) > 0 {
+		w.WriteHeader(statusCode)
+		io.Copy(w, content)
+		return
+	}
+	if len(etag) > 0 {
+		w.Header().Set("ETag", etag)
+	}
+	http.ServeContent(w, r, name, modTime, content)
+}
+
+// getETag returns a strong entity tag for content, derived from its hash.
+func getETag(content []byte) string {
+	return fmt.Sprintf(`"%x"`, sha256.Sum256(content))
+}
+
+// get returns the entity tag of f, whose file information is info, hashing f if it has changed since
+// it was last hashed. f is left at its start.
+func (fileETag *fileETag) get(f *os.File, info os.FileInfo) (string, error) {
+	fileETag.mutex.Lock()
+	defer fileETag.mutex.Unlock()
+
+	if len(fileETag.etag) > 0 && fileETag.modTime.Equal(info.ModTime()) && fileETag.size == info.Size() {
+		return fileETag.etag, nil
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	fileETag.modTime = info.ModTime()
+	fileETag.size = info.Size()
+	fileETag.etag = fmt.Sprintf(`"%x"`, hash.Sum(nil))
+	return fileETag.etag, nil
+}
+
+// getModTime returns the modification time of a file, or the zero time if it cannot be found.
+func getModTime(path string, file wrapper.IFileOps) time.Time {
+	info, err := file.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func writeStatusCode(statusCode int, w http.ResponseWriter) {
+	if statusCode > 0 && len(http.StatusText(statusCode)) > 0 {
+		w.WriteHeader(statusCode)
 	}
 }
 
@@ -126,6 +441,17 @@ func writeError(err error, w http.ResponseWriter) {
 	w.Write([]byte(err.Error()))
 }
 
+func writeViolations(msg string, violations []string, statusCode int, w http.ResponseWriter) {
+	body, _ := encodingJSON.Marshal(struct {
+		Message    string   `json:"message"`
+		Violations []string `json:"violations"`
+	}{msg, violations})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
+
 func (c creator) startAPI(defaultCert, defaultKey string, server wrapper.IServerOps, httpConfig config.HTTP) error {
 	contextLogger := c.log.WithFields(logrus.Fields{
 		log.FuncField:            ref.GetFuncName(),
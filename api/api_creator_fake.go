@@ -13,8 +13,8 @@ type fakeAPICreator struct {
 	mock.Mock
 }
 
-func (c *fakeAPICreator) getHandler(enforceValidJSON, allowCORS bool, statusCode int, headers []config.Header, dir, fileName string, file wrapper.IFileOps) func(w http.ResponseWriter, r *http.Request) {
-	args := c.Called(enforceValidJSON, dir, fileName, file)
+func (c *fakeAPICreator) getHandler(endpoint config.Endpoint, dir string, file wrapper.IFileOps) func(w http.ResponseWriter, r *http.Request) {
+	args := c.Called(endpoint.EnforceValidJSON, dir, endpoint.File, file)
 	return args.Get(0).(func(w http.ResponseWriter, r *http.Request))
 }
 
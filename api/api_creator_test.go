@@ -2,12 +2,17 @@ package api
 
 import (
 	"errors"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/wcsanders1/MockApiHub/config"
 	"github.com/wcsanders1/MockApiHub/fake"
+	"github.com/wcsanders1/MockApiHub/json"
 	"github.com/wcsanders1/MockApiHub/log"
 	"github.com/wcsanders1/MockApiHub/wrapper"
 
@@ -20,6 +25,11 @@ var goodJSON = []byte(`{
 	"test": "good"
 }`)
 
+var requiredNameSchema = []byte(`{
+	"type": "object",
+	"required": ["name"]
+}`)
+
 func TestNewCreator_ReturnsCreator_WhenCalled(t *testing.T) {
 	result := newCreator(log.GetFakeLogger())
 
@@ -33,7 +43,7 @@ func TestGetHandler_ReturnsHandler_WhenEnforceJSONFalse(t *testing.T) {
 		log: log.GetFakeLogger(),
 	}
 
-	result := creator.getHandler(false, false, 0, nil, "testDir", "testFile", &wrapper.FakeFileOps{})
+	result := creator.getHandler(config.Endpoint{File: "testFile"}, "testDir", &wrapper.FakeFileOps{})
 
 	assert := assert.New(t)
 	assert.NotNil(result)
@@ -45,29 +55,105 @@ func TestGetHandler_ReturnsHandler_WhenEnforceJSONTrue(t *testing.T) {
 		log: log.GetFakeLogger(),
 	}
 
-	result := creator.getHandler(true, false, 0, nil, "testDir", "testFile", &wrapper.FakeFileOps{})
+	result := creator.getHandler(config.Endpoint{File: "testFile", EnforceValidJSON: true}, "testDir", &wrapper.FakeFileOps{})
 
 	assert := assert.New(t)
 	assert.NotNil(result)
 	assert.IsType(func(w http.ResponseWriter, r *http.Request) {}, result)
 }
 
-func TestGetJSONHandler_ReturnsHandler_WhenCalled(t *testing.T) {
+func TestRequestValidationHandler_CallsNext_WhenBodyMatchesSchema(t *testing.T) {
+	fileOps := wrapper.FakeFileOps{}
+	fileOps.On("Open", mock.AnythingOfType("string")).Return(os.NewFile(1, "fakefile"), nil)
+	fileOps.On("ReadAll", mock.AnythingOfType("*os.File")).Return(requiredNameSchema, nil)
+	var nextBody []byte
+	next := func(w http.ResponseWriter, r *http.Request) {
+		nextBody, _ = ioutil.ReadAll(r.Body)
+	}
+	funcResult := getRequestValidationHandler("test/schema", 0, next, &fileOps, log.GetFakeLogger())
+	w := fake.ResponseWriter{}
+	body := `{"name": "Ada"}`
+	request, _ := http.NewRequest("POST", "test/url", strings.NewReader(body))
+
+	funcResult(&w, request)
+
+	fileOps.AssertCalled(t, "Open", "test/schema")
+	w.AssertNotCalled(t, "WriteHeader", mock.Anything)
+	assert.Equal(t, body, string(nextBody))
+}
+
+func TestRequestValidationHandler_WritesViolations_WhenBodyDoesNotMatchSchema(t *testing.T) {
+	fileOps := wrapper.FakeFileOps{}
+	fileOps.On("Open", mock.AnythingOfType("string")).Return(os.NewFile(1, "fakefile"), nil)
+	fileOps.On("ReadAll", mock.AnythingOfType("*os.File")).Return(requiredNameSchema, nil)
+	nextCalled := false
+	next := func(w http.ResponseWriter, r *http.Request) { nextCalled = true }
+	funcResult := getRequestValidationHandler("test/schema", http.StatusUnprocessableEntity, next, &fileOps, log.GetFakeLogger())
+	w := fake.ResponseWriter{}
+	w.On("Header").Return(http.Header{})
+	w.On("WriteHeader", mock.AnythingOfType("int")).Return(1)
+	w.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
+	request, _ := http.NewRequest("POST", "test/url", strings.NewReader(`{"age": 36}`))
+
+	funcResult(&w, request)
+
+	assert.False(t, nextCalled)
+	w.AssertCalled(t, "WriteHeader", http.StatusUnprocessableEntity)
+	w.AssertCalled(t, "Write", mock.AnythingOfType("[]uint8"))
+}
+
+func TestRequestValidationHandler_WritesBadRequest_WhenStatusCodeNotClientError(t *testing.T) {
+	fileOps := wrapper.FakeFileOps{}
+	fileOps.On("Open", mock.AnythingOfType("string")).Return(os.NewFile(1, "fakefile"), nil)
+	fileOps.On("ReadAll", mock.AnythingOfType("*os.File")).Return(requiredNameSchema, nil)
+	next := func(w http.ResponseWriter, r *http.Request) {}
+	funcResult := getRequestValidationHandler("test/schema", http.StatusOK, next, &fileOps, log.GetFakeLogger())
+	w := fake.ResponseWriter{}
+	w.On("Header").Return(http.Header{})
+	w.On("WriteHeader", mock.AnythingOfType("int")).Return(1)
+	w.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
+	request, _ := http.NewRequest("POST", "test/url", strings.NewReader(`not json`))
+
+	funcResult(&w, request)
+
+	w.AssertCalled(t, "WriteHeader", http.StatusBadRequest)
+}
+
+func TestRequestValidationHandler_WritesError_WhenSchemaCannotBeRead(t *testing.T) {
+	fileOps := wrapper.FakeFileOps{}
+	fileOps.On("Open", mock.AnythingOfType("string")).Return(os.NewFile(1, "fakefile"), errors.New(""))
+	nextCalled := false
+	next := func(w http.ResponseWriter, r *http.Request) { nextCalled = true }
+	funcResult := getRequestValidationHandler("test/schema", 0, next, &fileOps, log.GetFakeLogger())
+	w := fake.ResponseWriter{}
+	w.On("WriteHeader", mock.AnythingOfType("int")).Return(1)
+	w.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
+	request, _ := http.NewRequest("POST", "test/url", strings.NewReader(`{}`))
+
+	funcResult(&w, request)
+
+	assert.False(t, nextCalled)
+	w.AssertCalled(t, "WriteHeader", http.StatusInternalServerError)
+}
+
+func TestGetValidatedHandler_ReturnsHandler_WhenCalled(t *testing.T) {
 	fileOps := wrapper.FakeFileOps{}
 	logger := log.GetFakeLogger()
-	funcResult := getJSONHandler("test", nil, &fileOps, logger, false, 0)
+	funcResult := getValidatedHandler("test", json.ValidateJSON, nil, &fileOps, logger, false, 0, true)
 
 	assert.NotNil(t, funcResult)
 }
 
-func TestJSONHandler_Writes_OnSuccess(t *testing.T) {
+func TestValidatedHandler_Writes_OnSuccess(t *testing.T) {
 	path := "test/path"
 	fileOps := wrapper.FakeFileOps{}
 	logger := log.GetFakeLogger()
 	fileOps.On("Open", mock.AnythingOfType("string")).Return(os.NewFile(1, "fakefile"), nil)
 	fileOps.On("ReadAll", mock.AnythingOfType("*os.File")).Return(goodJSON, nil)
-	funcResult := getJSONHandler(path, nil, &fileOps, logger, false, 0)
+	fileOps.On("Stat", path).Return(new(fake.FileInfo), errors.New(""))
+	funcResult := getValidatedHandler(path, json.ValidateJSON, nil, &fileOps, logger, false, 0, true)
 	w := fake.ResponseWriter{}
+	w.On("Header").Return(http.Header{})
 	w.On("WriteHeader", mock.AnythingOfType("int")).Return(1)
 	w.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
 	request, _ := http.NewRequest("GET", "test/url", nil)
@@ -76,16 +162,16 @@ func TestJSONHandler_Writes_OnSuccess(t *testing.T) {
 
 	fileOps.AssertCalled(t, "Open", path)
 	fileOps.AssertCalled(t, "ReadAll", mock.AnythingOfType("*os.File"))
-	w.AssertCalled(t, "Write", mock.AnythingOfType("[]uint8"))
+	w.AssertCalled(t, "Write", goodJSON)
 }
 
-func TestJSONHandler_WritesError_OnFailure(t *testing.T) {
+func TestValidatedHandler_WritesError_OnFailure(t *testing.T) {
 	path := "test/path"
 	fileOps := wrapper.FakeFileOps{}
 	logger := log.GetFakeLogger()
 	fileOps.On("Open", mock.AnythingOfType("string")).Return(os.NewFile(1, "fakefile"), nil)
 	fileOps.On("ReadAll", mock.AnythingOfType("*os.File")).Return([]byte{}, errors.New(""))
-	funcResult := getJSONHandler(path, nil, &fileOps, logger, false, 0)
+	funcResult := getValidatedHandler(path, json.ValidateJSON, nil, &fileOps, logger, false, 0, true)
 	w := fake.ResponseWriter{}
 	w.On("WriteHeader", mock.AnythingOfType("int")).Return(1)
 	w.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
@@ -102,28 +188,24 @@ func TestJSONHandler_WritesError_OnFailure(t *testing.T) {
 func TestGetGeneralHanlder_ReturnsFunc_WhenCalled(t *testing.T) {
 	fileOps := wrapper.FakeFileOps{}
 	logger := log.GetFakeLogger()
-	funcResult := getGeneralHandler("test", nil, &fileOps, logger, false, 0)
+	funcResult := getGeneralHandler("test", nil, &fileOps, logger, false, 0, true)
 
 	assert.NotNil(t, funcResult)
 }
 
 func TestGeneralHandler_Writes_OnSuccess(t *testing.T) {
-	path := "test/path"
-	fileOps := wrapper.FakeFileOps{}
-	logger := log.GetFakeLogger()
-	fileOps.On("Open", mock.AnythingOfType("string")).Return(os.NewFile(1, "fakefile"), nil)
-	fileOps.On("ReadAll", mock.AnythingOfType("*os.File")).Return(goodJSON, nil)
-	funcResult := getGeneralHandler(path, nil, &fileOps, logger, false, 0)
-	w := fake.ResponseWriter{}
-	w.On("WriteHeader", mock.AnythingOfType("int")).Return(1)
-	w.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
+	dir := writeTempFile(t, "customers.json", goodJSON)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "customers.json")
+	funcResult := getGeneralHandler(path, nil, &wrapper.FileOps{}, log.GetFakeLogger(), false, 0, true)
+	w := httptest.NewRecorder()
 	request, _ := http.NewRequest("GET", "test/url", nil)
 
-	funcResult(&w, request)
+	funcResult(w, request)
 
-	fileOps.AssertCalled(t, "Open", path)
-	fileOps.AssertCalled(t, "ReadAll", mock.AnythingOfType("*os.File"))
-	w.AssertCalled(t, "Write", mock.AnythingOfType("[]uint8"))
+	assert := assert.New(t)
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Equal(goodJSON, w.Body.Bytes())
 }
 
 func TestGeneralHandler_WritesError_WhenReadFails(t *testing.T) {
@@ -132,7 +214,7 @@ func TestGeneralHandler_WritesError_WhenReadFails(t *testing.T) {
 	logger := log.GetFakeLogger()
 	fileOps.On("Open", mock.AnythingOfType("string")).Return(os.NewFile(1, "fakefile"), nil)
 	fileOps.On("ReadAll", mock.AnythingOfType("*os.File")).Return([]byte{}, errors.New(""))
-	funcResult := getGeneralHandler(path, nil, &fileOps, logger, false, 0)
+	funcResult := getGeneralHandler(path, nil, &envFileOps{&fileOps}, logger, false, 0, true)
 	w := fake.ResponseWriter{}
 	w.On("WriteHeader", mock.AnythingOfType("int")).Return(1)
 	w.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
@@ -151,7 +233,7 @@ func TestGeneralHandler_WritesError_WhenFileOpenFails(t *testing.T) {
 	fileOps := wrapper.FakeFileOps{}
 	logger := log.GetFakeLogger()
 	fileOps.On("Open", mock.AnythingOfType("string")).Return(os.NewFile(1, "fakefile"), errors.New(""))
-	funcResult := getGeneralHandler(path, nil, &fileOps, logger, false, 0)
+	funcResult := getGeneralHandler(path, nil, &fileOps, logger, false, 0, true)
 	w := fake.ResponseWriter{}
 	w.On("WriteHeader", mock.AnythingOfType("int")).Return(1)
 	w.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
@@ -322,3 +404,375 @@ func TestGetCertAndKeyFileNames_ReturnsError_WhenConfigProvidesCertButNotKey(t *
 	assert.Empty(key)
 	assert.Error(err)
 }
+
+func TestGetHandler_ServesBody_WhenNoFileProvided(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	fileOps := wrapper.FakeFileOps{}
+	w := fake.ResponseWriter{}
+	w.On("Header").Return(http.Header{})
+	w.On("WriteHeader", mock.AnythingOfType("int")).Return(1)
+	w.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
+	request, _ := http.NewRequest("GET", "test/url", nil)
+
+	handler := creator.getHandler(config.Endpoint{Body: `{"id": 1}`, EnforceValidJSON: true, HTTPStatusCode: 201}, "testDir", &fileOps)
+	handler(&w, request)
+
+	w.AssertCalled(t, "WriteHeader", http.StatusCreated)
+	w.AssertCalled(t, "Write", []byte(`{"id": 1}`))
+	fileOps.AssertNotCalled(t, "Open", mock.Anything)
+}
+
+func TestGetBodyHandler_WritesError_WhenBodyIsInvalidJSON(t *testing.T) {
+	w := fake.ResponseWriter{}
+	w.On("WriteHeader", mock.AnythingOfType("int")).Return(1)
+	w.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
+	request, _ := http.NewRequest("GET", "test/url", nil)
+
+	handler := getBodyHandler([]byte(`{"id": `), json.ValidateJSON, nil, log.GetFakeLogger(), false, 0)
+	handler(&w, request)
+
+	w.AssertCalled(t, "WriteHeader", http.StatusInternalServerError)
+	w.AssertNotCalled(t, "Write", []byte(`{"id": `))
+}
+
+func TestGetBodyHandler_WritesOnlyErrorStatus_WhenBodyIsInvalidAndStatusConfigured(t *testing.T) {
+	w := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "test/url", nil)
+
+	handler := getBodyHandler([]byte(`{"id": `), json.ValidateJSON, nil, log.GetFakeLogger(), false, http.StatusOK)
+	handler(w, request)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusInternalServerError, w.Code)
+	assert.NotEqual(`{"id": `, w.Body.String())
+}
+
+func TestGetHandler_ServesResponsesInTurn_WhenSequenceProvided(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	endpoint := config.Endpoint{
+		HTTPStatusCode: 200,
+		Headers:        []config.Header{{Key: "X-Endpoint", Value: "customers"}},
+		Sequence: []config.Response{
+			{Body: "first", HTTPStatusCode: 202},
+			{Body: "second", Headers: []config.Header{{Key: "X-Step", Value: "2"}}},
+		},
+	}
+	handler := creator.getHandler(endpoint, "testDir", &wrapper.FakeFileOps{})
+	request, _ := http.NewRequest("GET", "test/url", nil)
+	var statuses []int
+	var bodies []string
+	var headers []http.Header
+	for i := 0; i < 3; i++ {
+		header := http.Header{}
+		w := fake.ResponseWriter{}
+		w.On("Header").Return(header)
+		w.On("WriteHeader", mock.AnythingOfType("int")).Run(func(args mock.Arguments) {
+			statuses = append(statuses, args.Int(0))
+		}).Return(1)
+		w.On("Write", mock.AnythingOfType("[]uint8")).Run(func(args mock.Arguments) {
+			bodies = append(bodies, string(args.Get(0).([]byte)))
+		}).Return(1, nil)
+
+		handler(&w, request)
+		headers = append(headers, header)
+	}
+
+	assert := assert.New(t)
+	assert.Equal([]int{202, 200, 200}, statuses)
+	assert.Equal([]string{"first", "second", "second"}, bodies)
+	assert.Equal("customers", headers[0].Get("X-Endpoint"))
+	assert.Empty(headers[0].Get("X-Step"))
+	assert.Equal("2", headers[2].Get("X-Step"))
+	assert.Equal(1, len(endpoint.Headers))
+}
+
+func TestGetHandler_ExpandsEnvInFile_WhenExpandEnvSet(t *testing.T) {
+	os.Setenv("MOCKAPIHUB_TEST_HOST", "staging.example.com")
+	defer os.Unsetenv("MOCKAPIHUB_TEST_HOST")
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	fileOps := wrapper.FakeFileOps{}
+	fileOps.On("Open", mock.AnythingOfType("string")).Return(os.NewFile(1, "fakefile"), nil)
+	fileOps.On("ReadAll", mock.AnythingOfType("*os.File")).Return([]byte(`{"url": "https://${MOCKAPIHUB_TEST_HOST}/${MOCKAPIHUB_TEST_PATH:-customers}"}`), nil)
+	w := fake.ResponseWriter{}
+	w.On("Header").Return(http.Header{})
+	w.On("WriteHeader", mock.AnythingOfType("int")).Return(1)
+	w.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
+	request, _ := http.NewRequest("GET", "test/url", nil)
+
+	handler := creator.getHandler(config.Endpoint{File: "customers.json", EnforceValidJSON: true, ExpandEnv: true}, "testDir", &fileOps)
+	handler(&w, request)
+
+	w.AssertCalled(t, "Write", []byte(`{"url": "https://staging.example.com/customers"}`))
+}
+
+func TestGetHandler_ServesJSONTable_WhenNoFileProvided(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	header := http.Header{}
+	w := fake.ResponseWriter{}
+	w.On("Header").Return(header)
+	w.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
+	request, _ := http.NewRequest("GET", "test/url", nil)
+	endpoint := config.Endpoint{
+		JSON: map[string]interface{}{"id": int64(1), "tags": []interface{}{"a"}},
+		Body: "ignored",
+	}
+
+	handler := creator.getHandler(endpoint, "testDir", &wrapper.FakeFileOps{})
+	handler(&w, request)
+
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	w.AssertCalled(t, "Write", []byte(`{"id":1,"tags":["a"]}`))
+}
+
+func TestGetHandler_ServesFile_WhenFileAndInlineBodyProvided(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	dir := writeTempFile(t, "customers.json", goodJSON)
+	defer os.RemoveAll(dir)
+	w := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "test/url", nil)
+	endpoint := config.Endpoint{
+		File: "customers.json",
+		JSON: map[string]interface{}{"id": int64(1)},
+		Body: "ignored",
+	}
+
+	handler := creator.getHandler(endpoint, dir, &wrapper.FileOps{})
+	handler(w, request)
+
+	assert.Equal(t, goodJSON, w.Body.Bytes())
+}
+
+func TestGetHandler_InfersContentType_WhenFileHasKnownExtension(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	dir := writeTempFile(t, "report.pdf", []byte("%PDF-1.4"))
+	defer os.RemoveAll(dir)
+	writeTempFileIn(t, dir, "orders.json", goodJSON)
+	request, _ := http.NewRequest("GET", "test/url", nil)
+	pdf := httptest.NewRecorder()
+	orders := httptest.NewRecorder()
+
+	creator.getHandler(config.Endpoint{File: "report.pdf"}, dir, &wrapper.FileOps{})(pdf, request)
+	creator.getHandler(config.Endpoint{File: "orders.json"}, dir, &wrapper.FileOps{})(orders, request)
+
+	assert := assert.New(t)
+	assert.Equal("application/pdf", pdf.Header().Get("Content-Type"))
+	assert.Equal("application/json", orders.Header().Get("Content-Type"))
+}
+
+func TestGetHandler_OverridesContentType_WhenConfigured(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	dir := writeTempFile(t, "orders.json", goodJSON)
+	defer os.RemoveAll(dir)
+	request, _ := http.NewRequest("GET", "test/url", nil)
+	configured := httptest.NewRecorder()
+	header := httptest.NewRecorder()
+
+	creator.getHandler(config.Endpoint{File: "orders.json", ContentType: "application/vnd.orders+json"}, dir, &wrapper.FileOps{})(configured, request)
+	creator.getHandler(config.Endpoint{
+		File:        "orders.json",
+		ContentType: "application/vnd.orders+json",
+		Headers:     []config.Header{{Key: "Content-Type", Value: "text/plain"}},
+	}, dir, &wrapper.FileOps{})(header, request)
+
+	assert := assert.New(t)
+	assert.Equal("application/vnd.orders+json", configured.Header().Get("Content-Type"))
+	assert.Equal("text/plain", header.Header().Get("Content-Type"))
+}
+
+func TestGeneralHandler_WritesPartialContent_WhenRangeRequested(t *testing.T) {
+	dir := writeTempFile(t, "archive.bin", []byte("0123456789"))
+	defer os.RemoveAll(dir)
+	funcResult := getGeneralHandler(filepath.Join(dir, "archive.bin"), nil, &wrapper.FileOps{}, log.GetFakeLogger(), false, 0, true)
+	w := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "test/url", nil)
+	request.Header.Set("Range", "bytes=2-5")
+
+	funcResult(w, request)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusPartialContent, w.Code)
+	assert.Equal("bytes 2-5/10", w.Header().Get("Content-Range"))
+	assert.Equal("2345", w.Body.String())
+}
+
+func TestGeneralHandler_IgnoresRange_WhenStatusCodeConfigured(t *testing.T) {
+	dir := writeTempFile(t, "archive.bin", []byte("0123456789"))
+	defer os.RemoveAll(dir)
+	funcResult := getGeneralHandler(filepath.Join(dir, "archive.bin"), nil, &wrapper.FileOps{}, log.GetFakeLogger(), false, http.StatusAccepted, true)
+	w := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "test/url", nil)
+	request.Header.Set("Range", "bytes=2-5")
+
+	funcResult(w, request)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusAccepted, w.Code)
+	assert.Equal("0123456789", w.Body.String())
+}
+
+func TestGetHandler_WritesValidators_WhenServingFile(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	dir := writeTempFile(t, "orders.json", goodJSON)
+	defer os.RemoveAll(dir)
+	request, _ := http.NewRequest("GET", "test/url", nil)
+	general := httptest.NewRecorder()
+	validated := httptest.NewRecorder()
+
+	creator.getHandler(config.Endpoint{File: "orders.json"}, dir, &wrapper.FileOps{})(general, request)
+	creator.getHandler(config.Endpoint{File: "orders.json", EnforceValidJSON: true}, dir, &wrapper.FileOps{})(validated, request)
+
+	assert := assert.New(t)
+	assert.Equal(getETag(goodJSON), general.Header().Get("ETag"))
+	assert.NotEmpty(general.Header().Get("Last-Modified"))
+	assert.Equal(getETag(goodJSON), validated.Header().Get("ETag"))
+	assert.NotEmpty(validated.Header().Get("Last-Modified"))
+}
+
+func TestGetHandler_RehashesFile_WhenFileChanges(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	dir := writeTempFile(t, "orders.json", goodJSON)
+	defer os.RemoveAll(dir)
+	handler := creator.getHandler(config.Endpoint{File: "orders.json"}, dir, &wrapper.FileOps{})
+	request, _ := http.NewRequest("GET", "test/url", nil)
+	first := httptest.NewRecorder()
+	second := httptest.NewRecorder()
+	changed := []byte(`{"id": 2}`)
+
+	handler(first, request)
+	writeTempFileIn(t, dir, "orders.json", changed)
+	handler(second, request)
+
+	assert := assert.New(t)
+	assert.Equal(getETag(goodJSON), first.Header().Get("ETag"))
+	assert.Equal(getETag(changed), second.Header().Get("ETag"))
+	assert.Equal(string(changed), second.Body.String())
+}
+
+func TestGetHandler_WritesNotModified_WhenValidatorsMatch(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	dir := writeTempFile(t, "orders.json", goodJSON)
+	defer os.RemoveAll(dir)
+	handler := creator.getHandler(config.Endpoint{File: "orders.json"}, dir, &wrapper.FileOps{})
+	first := httptest.NewRecorder()
+	byETag := httptest.NewRecorder()
+	byDate := httptest.NewRecorder()
+	changed := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "test/url", nil)
+
+	handler(first, request)
+	request.Header.Set("If-None-Match", first.Header().Get("ETag"))
+	handler(byETag, request)
+	request.Header.Del("If-None-Match")
+	request.Header.Set("If-Modified-Since", first.Header().Get("Last-Modified"))
+	handler(byDate, request)
+	request.Header.Del("If-Modified-Since")
+	request.Header.Set("If-None-Match", `"stale"`)
+	handler(changed, request)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusNotModified, byETag.Code)
+	assert.Empty(byETag.Body.String())
+	assert.Equal(http.StatusNotModified, byDate.Code)
+	assert.Equal(http.StatusOK, changed.Code)
+	assert.Equal(goodJSON, changed.Body.Bytes())
+}
+
+func TestGetHandler_WritesFullBody_WhenCacheHeadersDisabled(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	dir := writeTempFile(t, "orders.json", goodJSON)
+	defer os.RemoveAll(dir)
+	w := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "test/url", nil)
+	request.Header.Set("If-None-Match", getETag(goodJSON))
+
+	creator.getHandler(config.Endpoint{File: "orders.json", DisableCacheHeaders: true}, dir, &wrapper.FileOps{})(w, request)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Equal(goodJSON, w.Body.Bytes())
+	assert.Empty(w.Header().Get("ETag"))
+	assert.Empty(w.Header().Get("Last-Modified"))
+}
+
+func TestGetContentType_ReturnsEmpty_WhenExtensionUnknown(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("application/x-protobuf", getContentType("customers.PB"))
+	assert.Equal("text/csv; charset=utf-8", getContentType("customers.csv"))
+	assert.Empty(getContentType("customers"))
+	assert.Empty(getContentType("customers.unknownext"))
+}
+
+func TestGetHandler_ServesXML_WhenEnforceValidXMLAndFileValid(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	dir := writeTempFile(t, "invoice.soap", []byte("<Envelope><Body/></Envelope>"))
+	defer os.RemoveAll(dir)
+	w := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "test/url", nil)
+
+	creator.getHandler(config.Endpoint{File: "invoice.soap", EnforceValidXML: true}, dir, &wrapper.FileOps{})(w, request)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Equal("application/xml", w.Header().Get("Content-Type"))
+	assert.Equal("<Envelope><Body/></Envelope>", w.Body.String())
+}
+
+func TestGetHandler_WritesError_WhenEnforceValidXMLAndBodyInvalid(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	dir := writeTempFile(t, "invoice.xml", []byte("<Envelope><Body></Envelope>"))
+	defer os.RemoveAll(dir)
+	request, _ := http.NewRequest("POST", "test/url", nil)
+	fileResponse := httptest.NewRecorder()
+	cachedResponse := httptest.NewRecorder()
+	bodyResponse := httptest.NewRecorder()
+
+	creator.getHandler(config.Endpoint{File: "invoice.xml", EnforceValidXML: true}, dir, &wrapper.FileOps{})(fileResponse, request)
+	creator.getHandler(config.Endpoint{File: "invoice.xml", EnforceValidXML: true, CacheFile: true}, dir, &wrapper.FileOps{})(cachedResponse, request)
+	creator.getHandler(config.Endpoint{Body: "<Envelope>", EnforceValidXML: true}, dir, &wrapper.FileOps{})(bodyResponse, request)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusInternalServerError, fileResponse.Code)
+	assert.Equal(http.StatusInternalServerError, cachedResponse.Code)
+	assert.Equal(http.StatusInternalServerError, bodyResponse.Code)
+}
+
+func writeTempFile(t *testing.T, name string, contents []byte) string {
+	dir, err := ioutil.TempDir("", "mockApiHub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTempFileIn(t, dir, name, contents)
+	return dir
+}
+
+func writeTempFileIn(t *testing.T, dir, name string, contents []byte) {
+	if err := ioutil.WriteFile(filepath.Join(dir, name), contents, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
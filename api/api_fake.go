@@ -46,3 +46,21 @@ func (api *FakeAPI) GetEndpoints() map[string]config.Endpoint {
 	args := api.Called()
 	return args.Get(0).(map[string]config.Endpoint)
 }
+
+// GetResponseSchemaViolations is a mockable api.GetResponseSchemaViolations().
+func (api *FakeAPI) GetResponseSchemaViolations() map[string][]string {
+	args := api.Called()
+	return args.Get(0).(map[string][]string)
+}
+
+// GetReceivedFrames is a mockable api.GetReceivedFrames().
+func (api *FakeAPI) GetReceivedFrames() map[string][]ReceivedFrame {
+	args := api.Called()
+	return args.Get(0).(map[string][]ReceivedFrame)
+}
+
+// GetProtocol is a mockable api.GetProtocol().
+func (api *FakeAPI) GetProtocol() string {
+	args := api.Called()
+	return args.String(0)
+}
@@ -2,7 +2,11 @@ package api
 
 import (
 	"errors"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/wcsanders1/MockApiHub/config"
@@ -30,11 +34,25 @@ func TestNewAPI_ReturnsNewAPI_WhenProvidedPort(t *testing.T) {
 	assert.NoError(err)
 }
 
-func TestNewAPI_ReturnsError_WhenNotProvidedPort(t *testing.T) {
+func TestNewAPI_ReturnsNewAPI_WhenPortIsZero(t *testing.T) {
 	config := &config.APIConfig{}
 
 	result, err := NewAPI(config)
 
+	assert := assert.New(t)
+	assert.NotNil(result)
+	assert.NoError(err)
+}
+
+func TestNewAPI_ReturnsError_WhenPortIsNegative(t *testing.T) {
+	config := &config.APIConfig{
+		HTTP: config.HTTP{
+			Port: -1,
+		},
+	}
+
+	result, err := NewAPI(config)
+
 	assert := assert.New(t)
 	assert.Nil(result)
 	assert.Error(err)
@@ -65,9 +83,11 @@ func TestStart_DeletesEndpoint_WhenEndpointIsDuplicate(t *testing.T) {
 			Method: "GET",
 		},
 	}
+	server := wrapper.FakeServerOps{}
+	server.On("Listen").Return(4000, nil)
 	testAPI := API{
-		server:     &wrapper.FakeServerOps{},
-		httpConfig: config.HTTP{},
+		server:     &server,
+		httpConfig: config.HTTP{Port: 4000},
 		endpoints:  endpoints,
 		baseURL:    baseURL,
 		routeTree:  &routeTree,
@@ -83,6 +103,77 @@ func TestStart_DeletesEndpoint_WhenEndpointIsDuplicate(t *testing.T) {
 	assert.Equal(1, len(testAPI.endpoints))
 }
 
+func TestStart_KeepsEndpoints_WhenSameRouteHasDifferentQueryConditions(t *testing.T) {
+	path := "accounts"
+	creator := fakeAPICreator{}
+	creator.On("getHandler", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(func(w http.ResponseWriter, r *http.Request) {})
+	creator.On("startAPI", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	routeTree := route.FakeTree{}
+	routeTree.On("GetRoute", mock.AnythingOfType("string")).Return(path, map[string]string{}, nil)
+	endpoints := map[string]config.Endpoint{
+		"getAccounts": config.Endpoint{
+			Path:   path,
+			Method: "GET",
+		},
+		"getClosedAccounts": config.Endpoint{
+			Path:   path,
+			Method: "GET",
+			Query: []config.Matcher{
+				config.Matcher{Key: "status", Value: "closed"},
+			},
+		},
+	}
+	server := wrapper.FakeServerOps{}
+	server.On("Listen").Return(4000, nil)
+	testAPI := API{
+		server:     &server,
+		httpConfig: config.HTTP{Port: 4000},
+		endpoints:  endpoints,
+		routeTree:  &routeTree,
+		log:        log.GetFakeLogger(),
+		creator:    &creator,
+		handlers:   make(map[string]map[string]func(http.ResponseWriter, *http.Request)),
+	}
+
+	err := testAPI.Start("testDir", "testCert", "testKey")
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal(2, len(testAPI.endpoints))
+	assert.Equal(2, len(testAPI.matchedHandlers["GET"][path]))
+	assert.Equal("getClosedAccounts", testAPI.matchedHandlers["GET"][path][0].endpointName)
+}
+
+func TestMatchingHandler_WritesStatusNotFound_WhenNoEndpointMatches(t *testing.T) {
+	method := "GET"
+	path := "accounts"
+	matcher, _ := newRequestMatcher(config.Endpoint{
+		Query: []config.Matcher{
+			config.Matcher{Key: "status", Value: "closed"},
+		},
+	})
+	testAPI := API{
+		log:             log.GetFakeLogger(),
+		handlers:        make(map[string]map[string]func(http.ResponseWriter, *http.Request)),
+		matchedHandlers: make(map[string]map[string][]matchedHandler),
+	}
+	handlerCalled := false
+	testAPI.addMatchedHandler(method, path, matchedHandler{
+		endpointName: "getClosedAccounts",
+		matcher:      matcher,
+		handler:      func(w http.ResponseWriter, r *http.Request) { handlerCalled = true },
+	})
+	writer := fake.ResponseWriter{}
+	writer.On("WriteHeader", mock.AnythingOfType("int")).Return(1)
+	writer.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
+	request, _ := http.NewRequest(method, "accounts?status=open", nil)
+
+	testAPI.handlers[method][path](&writer, request)
+
+	assert.False(t, handlerCalled)
+	writer.AssertCalled(t, "WriteHeader", http.StatusNotFound)
+}
+
 func TestStart_ReturnsNil_WhenStartSuccessful(t *testing.T) {
 	endpointName := "testEndpoint"
 	baseURL := "baseURL"
@@ -102,9 +193,11 @@ func TestStart_ReturnsNil_WhenStartSuccessful(t *testing.T) {
 			File: file,
 		},
 	}
+	server := wrapper.FakeServerOps{}
+	server.On("Listen").Return(4000, nil)
 	testAPI := API{
-		server:     &wrapper.FakeServerOps{},
-		httpConfig: config.HTTP{},
+		server:     &server,
+		httpConfig: config.HTTP{Port: 4000},
 		endpoints:  endpoints,
 		baseURL:    baseURL,
 		routeTree:  &routeTree,
@@ -119,6 +212,68 @@ func TestStart_ReturnsNil_WhenStartSuccessful(t *testing.T) {
 	creator.AssertCalled(t, "startAPI", cert, key, mock.Anything, mock.Anything)
 }
 
+func TestStart_AssignsFreePort_WhenPortIsZero(t *testing.T) {
+	creator := fakeAPICreator{}
+	creator.On("startAPI", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	server := wrapper.FakeServerOps{}
+	server.On("Listen").Return(5001, nil)
+	testAPI := API{
+		server:    &server,
+		endpoints: map[string]config.Endpoint{},
+		log:       log.GetFakeLogger(),
+		creator:   &creator,
+		handlers:  make(map[string]map[string]func(http.ResponseWriter, *http.Request)),
+	}
+
+	err := testAPI.Start("testDir", "testCert", "testKey")
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal(5001, testAPI.GetPort())
+	creator.AssertCalled(t, "startAPI", "testCert", "testKey", &server, config.HTTP{Port: 5001})
+}
+
+func TestStart_KeepsPort_WhenPortIsFixed(t *testing.T) {
+	creator := fakeAPICreator{}
+	creator.On("startAPI", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	server := wrapper.FakeServerOps{}
+	server.On("Listen").Return(4000, nil)
+	testAPI := API{
+		server:     &server,
+		httpConfig: config.HTTP{Port: 4000},
+		endpoints:  map[string]config.Endpoint{},
+		log:        log.GetFakeLogger(),
+		creator:    &creator,
+		handlers:   make(map[string]map[string]func(http.ResponseWriter, *http.Request)),
+	}
+
+	err := testAPI.Start("testDir", "testCert", "testKey")
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal(4000, testAPI.GetPort())
+	server.AssertCalled(t, "Listen")
+	creator.AssertCalled(t, "startAPI", "testCert", "testKey", &server, config.HTTP{Port: 4000})
+}
+
+func TestStart_ReturnsError_WhenListenFails(t *testing.T) {
+	creator := fakeAPICreator{}
+	server := wrapper.FakeServerOps{}
+	server.On("Listen").Return(0, errors.New(""))
+	testAPI := API{
+		server:    &server,
+		endpoints: map[string]config.Endpoint{},
+		log:       log.GetFakeLogger(),
+		creator:   &creator,
+		handlers:  make(map[string]map[string]func(http.ResponseWriter, *http.Request)),
+	}
+
+	err := testAPI.Start("testDir", "testCert", "testKey")
+
+	assert.Error(t, err)
+	creator.AssertNotCalled(t, "startAPI", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestShutdown_ReturnsNil_WhenShutdownSuccessful(t *testing.T) {
 	fakeServer := wrapper.FakeServerOps{}
 	fakeServer.On("Shutdown", mock.Anything).Return(nil)
@@ -236,7 +391,7 @@ func TestGetPort_ReturnsPort_WhenCalled(t *testing.T) {
 	port := 4000
 	testAPI := API{
 		httpConfig: config.HTTP{
-			Port: port,
+			Port: config.Port(port),
 		},
 	}
 
@@ -314,6 +469,41 @@ func TestEnsureRouteRegistered_DoesNotAddRoute_IfRegistered(t *testing.T) {
 	fakeRouteTree.AssertNotCalled(t, "AddRoute", mock.Anything)
 }
 
+func TestEnsureRouteRegistered_KeepsTrailingSlash_WhenStrictTrailingSlash(t *testing.T) {
+	url := "test/url/"
+	fakeRouteTree := &route.FakeTree{}
+	fakeRouteTree.On("GetRoute", mock.AnythingOfType("string")).Return("", map[string]string{}, nil)
+	fakeRouteTree.On("AddRoute", mock.AnythingOfType("string")).Return(url, nil)
+	api := &API{
+		routeTree:           fakeRouteTree,
+		strictTrailingSlash: true,
+	}
+
+	result := api.ensureRouteRegistered(url)
+
+	assert.Equal(t, url, result)
+	fakeRouteTree.AssertCalled(t, "AddRoute", url)
+}
+
+func TestServeHTTP_PreservesCase_WhenCaseSensitive(t *testing.T) {
+	path := "/Test/Path"
+	routeTree := route.FakeTree{}
+	routeTree.On("GetRoute", mock.AnythingOfType("string")).Return(path, map[string]string{}, nil)
+	writer := fake.ResponseWriter{}
+	writer.On("WriteHeader", mock.AnythingOfType("int")).Return(1)
+	writer.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
+	request, _ := http.NewRequest("GET", path, nil)
+	testAPI := API{
+		log:           log.GetFakeLogger(),
+		routeTree:     &routeTree,
+		caseSensitive: true,
+	}
+
+	testAPI.ServeHTTP(&writer, request)
+
+	routeTree.AssertCalled(t, "GetRoute", "Test/Path")
+}
+
 func TestCreateAPIServer_ReturnsServer_WhenPortProvided(t *testing.T) {
 	httpConfig := &config.HTTP{
 		Port: 4000,
@@ -328,9 +518,9 @@ func TestCreateAPIServer_ReturnsServer_WhenPortProvided(t *testing.T) {
 	assert.IsType(&http.Server{}, result)
 }
 
-func TestCreateAPIServer_ReturnsError_WhenPortNotProvided(t *testing.T) {
+func TestCreateAPIServer_ReturnsError_WhenPortIsNegative(t *testing.T) {
 	httpConfig := &config.HTTP{
-		Port: 0,
+		Port: -1,
 	}
 	api, _ := NewAPI(&config.APIConfig{})
 
@@ -340,3 +530,63 @@ func TestCreateAPIServer_ReturnsError_WhenPortNotProvided(t *testing.T) {
 	assert.Nil(result)
 	assert.Error(err)
 }
+
+func TestStart_ServesFilePerSOAPOperation_WhenEndpointsShareRoute(t *testing.T) {
+	dir := writeTempFile(t, "getInvoice.xml", []byte("<GetInvoiceResponse/>"))
+	defer os.RemoveAll(dir)
+	writeTempFileIn(t, dir, "fault.xml", []byte("<Fault/>"))
+	writeTempFileIn(t, dir, "payInvoice.xml", []byte("<PayInvoiceResponse/>"))
+	testAPI, _ := NewAPI(&config.APIConfig{
+		BaseURL: "billing",
+		Endpoints: map[string]config.Endpoint{
+			"getInvoice": config.Endpoint{
+				Path:       "service",
+				Method:     "POST",
+				File:       "getInvoice.xml",
+				SOAPAction: "urn:billing/GetInvoice",
+			},
+			"getMissingInvoice": config.Endpoint{
+				Path:           "service",
+				Method:         "POST",
+				File:           "fault.xml",
+				HTTPStatusCode: http.StatusInternalServerError,
+				SOAPAction:     "urn:billing/GetInvoice",
+				XPath:          []config.Matcher{config.Matcher{Key: "//invoiceId", Value: "0"}},
+			},
+			"payInvoice": config.Endpoint{
+				Path:   "service",
+				Method: "POST",
+				File:   "payInvoice.xml",
+				XPath:  []config.Matcher{config.Matcher{Key: "//PayInvoice"}},
+			},
+		},
+	})
+	testAPI.Start(dir, "", "")
+	defer testAPI.Shutdown()
+	post := func(soapAction, body string) (int, string) {
+		request, _ := http.NewRequest("POST", fmt.Sprintf("http://localhost:%d/billing/service", testAPI.GetPort()), strings.NewReader(
+			`<Envelope xmlns="http://schemas.xmlsoap.org/soap/envelope/"><Body>`+body+`</Body></Envelope>`))
+		request.Header.Set("SOAPAction", soapAction)
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer response.Body.Close()
+		contents, _ := ioutil.ReadAll(response.Body)
+		return response.StatusCode, string(contents)
+	}
+
+	getStatus, getBody := post(`"urn:billing/GetInvoice"`, "<GetInvoice><invoiceId>42</invoiceId></GetInvoice>")
+	missingStatus, missingBody := post(`"urn:billing/GetInvoice"`, "<GetInvoice><invoiceId>0</invoiceId></GetInvoice>")
+	payStatus, payBody := post("", "<PayInvoice><invoiceId>42</invoiceId></PayInvoice>")
+	unknownStatus, _ := post(`"urn:billing/VoidInvoice"`, "<VoidInvoice/>")
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusOK, getStatus)
+	assert.Equal("<GetInvoiceResponse/>", getBody)
+	assert.Equal(http.StatusInternalServerError, missingStatus)
+	assert.Equal("<Fault/>", missingBody)
+	assert.Equal(http.StatusOK, payStatus)
+	assert.Equal("<PayInvoiceResponse/>", payBody)
+	assert.Equal(http.StatusNotFound, unknownStatus)
+}
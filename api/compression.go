@@ -0,0 +1,247 @@
+package api
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/andybalholm/brotli"
+	"github.com/sirupsen/logrus"
+)
+
+// The values of an endpoint's compression setting, besides an encoding, which is applied to every
+// response regardless of the request.
+const (
+	CompressionOff           = "off"
+	CompressionAuto          = "auto"
+	CompressionPrecompressed = "precompressed"
+)
+
+const (
+	encodingBrotli  = "br"
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+// encodings are the encodings responses can be compressed with, in order of preference.
+var encodings = []string{encodingBrotli, encodingGzip, encodingDeflate}
+
+// precompressedEncodings are the encodings of precompressed files, in order of preference, with the
+// extensions added to the name of the uncompressed file to name them.
+var precompressedEncodings = []struct {
+	encoding  string
+	extension string
+}{
+	{encodingBrotli, ".br"},
+	{encodingGzip, ".gz"},
+}
+
+type compressionWriter struct {
+	http.ResponseWriter
+	encoding    string
+	encoder     io.WriteCloser
+	wroteHeader bool
+	headOnly    bool
+}
+
+// IsCompressionMode returns whether mode is a valid value of an endpoint's compression setting.
+func IsCompressionMode(mode string) bool {
+	switch strings.ToLower(mode) {
+	case "", CompressionOff, CompressionAuto, CompressionPrecompressed:
+		return true
+	}
+	return isEncoding(strings.ToLower(mode))
+}
+
+func isEncoding(encoding string) bool {
+	for _, supported := range encodings {
+		if encoding == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// getCompressionHandler returns a handler that compresses the responses of next as the mode
+// requires. Precompressed files are served by getPrecompressedHandler instead.
+func getCompressionHandler(mode string, next func(w http.ResponseWriter, r *http.Request), logger *logrus.Entry) func(w http.ResponseWriter, r *http.Request) {
+	mode = strings.ToLower(mode)
+	switch {
+	case mode == CompressionAuto:
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), encodings)
+			if len(encoding) == 0 {
+				next(w, r)
+				return
+			}
+			compress(w, r, encoding, next)
+		}
+	case isEncoding(mode):
+		return func(w http.ResponseWriter, r *http.Request) {
+			compress(w, r, mode, next)
+		}
+	case len(mode) > 0 && !IsCompressionMode(mode):
+		logger.WithField("compression", mode).Warn("unknown compression mode -- responses are not compressed")
+	}
+	return next
+}
+
+// compress serves the response of next compressed with the encoding. Since the response is
+// compressed as it is written, ranges of it cannot be served. The response to a HEAD request has
+// the headers of a compressed response but no body.
+func compress(w http.ResponseWriter, r *http.Request, encoding string, next func(w http.ResponseWriter, r *http.Request)) {
+	header := make(http.Header, len(r.Header))
+	for key, values := range r.Header {
+		header[key] = values
+	}
+	header.Del("Range")
+	r = r.WithContext(r.Context())
+	r.Header = header
+
+	cw := &compressionWriter{
+		ResponseWriter: w,
+		encoding:       encoding,
+		headOnly:       r.Method == http.MethodHead,
+	}
+	defer cw.Close()
+	next(cw, r)
+}
+
+// getPrecompressedHandler returns a handler that serves, instead of the file at path, a compressed
+// version of it, such as path.gz, if one exists and the request accepts its encoding. Otherwise,
+// the response of identity is served.
+func getPrecompressedHandler(path string, headers []config.Header, file wrapper.IFileOps, logger *logrus.Entry, allowCORS bool, statusCode int, cacheHeaders bool, identity func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+	if env, transforms := file.(*envFileOps); transforms {
+		file = env.IFileOps
+	}
+
+	handlers := make(map[string]func(w http.ResponseWriter, r *http.Request), len(precompressedEncodings))
+	for _, precompressed := range precompressedEncodings {
+		encodedHeaders := append(append([]config.Header{}, headers...), config.Header{Key: "Content-Encoding", Value: precompressed.encoding})
+		handlers[precompressed.encoding] = getGeneralHandler(path+precompressed.extension, encodedHeaders, file, logger, allowCORS, statusCode, cacheHeaders)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		var available []string
+		for _, precompressed := range precompressedEncodings {
+			if _, err := file.Stat(path + precompressed.extension); err == nil {
+				available = append(available, precompressed.encoding)
+			}
+		}
+
+		if encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), available); len(encoding) > 0 {
+			handlers[encoding](w, r)
+			return
+		}
+		identity(w, r)
+	}
+}
+
+// negotiateEncoding returns the encoding, of those available, that an Accept-Encoding header
+// prefers, or an empty string if it accepts none of them. Ties go to the first available encoding.
+func negotiateEncoding(acceptEncoding string, available []string) string {
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(part, ";")
+		encoding := strings.ToLower(strings.TrimSpace(params[0]))
+		if len(encoding) == 0 {
+			continue
+		}
+		weight := 1.0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					weight = q
+				}
+			}
+		}
+		weights[encoding] = weight
+	}
+
+	best := ""
+	bestWeight := 0.0
+	for _, encoding := range available {
+		weight, exists := weights[encoding]
+		if !exists {
+			weight = weights["*"]
+		}
+		if weight > bestWeight {
+			best = encoding
+			bestWeight = weight
+		}
+	}
+	return best
+}
+
+// WriteHeader starts compressing the response, unless it has no body or is already encoded.
+func (w *compressionWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	header := w.Header()
+	if statusCode >= http.StatusOK && statusCode != http.StatusNoContent && statusCode != http.StatusNotModified && len(header.Get("Content-Encoding")) == 0 {
+		header.Del("Content-Length")
+		header.Set("Content-Encoding", w.encoding)
+		// The compressed response is only semantically equivalent to the uncompressed one.
+		if etag := header.Get("ETag"); len(etag) > 0 && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+		if !w.headOnly {
+			w.encoder = newEncoder(w.encoding, w.ResponseWriter)
+		}
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *compressionWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.headOnly {
+		return len(data), nil
+	}
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Flush writes what has been compressed so far.
+func (w *compressionWriter) Flush() {
+	if flusher, ok := w.encoder.(interface{ Flush() error }); ok {
+		flusher.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *compressionWriter) Close() error {
+	if w.encoder == nil {
+		return nil
+	}
+	return w.encoder.Close()
+}
+
+func newEncoder(encoding string, w io.Writer) io.WriteCloser {
+	switch encoding {
+	case encodingBrotli:
+		return brotli.NewWriter(w)
+	case encodingDeflate:
+		return zlib.NewWriter(w)
+	default:
+		return gzip.NewWriter(w)
+	}
+}
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/log"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+)
+
+func getCompressedResponse(t *testing.T, endpoint config.Endpoint, acceptEncoding string) *httptest.ResponseRecorder {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	dir := writeTempFile(t, "orders.json", goodJSON)
+	defer os.RemoveAll(dir)
+	endpoint.File = "orders.json"
+	w := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "test/url", nil)
+	if len(acceptEncoding) > 0 {
+		request.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+
+	creator.getHandler(endpoint, dir, &wrapper.FileOps{})(w, request)
+	return w
+}
+
+func TestNegotiateEncoding_ReturnsPreferredEncoding_WhenAccepted(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("gzip", negotiateEncoding("gzip, deflate", encodings))
+	assert.Equal("br", negotiateEncoding("gzip, deflate, br", encodings))
+	assert.Equal("deflate", negotiateEncoding("gzip;q=0.5, deflate", encodings))
+	assert.Equal("br", negotiateEncoding("*", encodings))
+	assert.Equal("gzip", negotiateEncoding("*, br;q=0", encodings))
+	assert.Empty(negotiateEncoding("gzip;q=0, identity", encodings))
+	assert.Empty(negotiateEncoding("", encodings))
+	assert.Empty(negotiateEncoding("gzip", nil))
+}
+
+func TestIsCompressionMode_ReturnsFalse_WhenModeUnknown(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(IsCompressionMode(""))
+	assert.True(IsCompressionMode("Auto"))
+	assert.True(IsCompressionMode("precompressed"))
+	assert.True(IsCompressionMode("br"))
+	assert.False(IsCompressionMode("zip"))
+}
+
+func TestGetHandler_CompressesResponse_WhenRequestAcceptsEncoding(t *testing.T) {
+	w := getCompressedResponse(t, config.Endpoint{Compression: CompressionAuto}, "gzip")
+	reader, err := gzip.NewReader(w.Body)
+	assert.Nil(t, err)
+	result, err := ioutil.ReadAll(reader)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal(goodJSON, result)
+	assert.Equal("gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal("Accept-Encoding", w.Header().Get("Vary"))
+	assert.Equal("W/"+getETag(goodJSON), w.Header().Get("ETag"))
+	assert.Empty(w.Header().Get("Content-Length"))
+}
+
+func TestGetHandler_DoesNotCompress_WhenRequestAcceptsNoEncoding(t *testing.T) {
+	w := getCompressedResponse(t, config.Endpoint{Compression: CompressionAuto}, "")
+
+	assert := assert.New(t)
+	assert.Equal(goodJSON, w.Body.Bytes())
+	assert.Empty(w.Header().Get("Content-Encoding"))
+	assert.Equal("Accept-Encoding", w.Header().Get("Vary"))
+}
+
+func TestGetHandler_CompressesResponse_WhenEncodingForced(t *testing.T) {
+	deflated := getCompressedResponse(t, config.Endpoint{Compression: "deflate"}, "")
+	brotliEncoded := getCompressedResponse(t, config.Endpoint{Compression: "br"}, "gzip")
+	reader, err := zlib.NewReader(deflated.Body)
+	assert.Nil(t, err)
+	deflatedResult, deflatedErr := ioutil.ReadAll(reader)
+	brotliResult, brotliErr := ioutil.ReadAll(brotli.NewReader(brotliEncoded.Body))
+
+	assert := assert.New(t)
+	assert.Nil(deflatedErr)
+	assert.Nil(brotliErr)
+	assert.Equal("deflate", deflated.Header().Get("Content-Encoding"))
+	assert.Equal(goodJSON, deflatedResult)
+	assert.Equal("br", brotliEncoded.Header().Get("Content-Encoding"))
+	assert.Equal(goodJSON, brotliResult)
+}
+
+func TestGetHandler_WritesContentEncodingWithoutBody_WhenHeadRequested(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	dir := writeTempFile(t, "orders.json", goodJSON)
+	defer os.RemoveAll(dir)
+	forced := httptest.NewRecorder()
+	negotiated := httptest.NewRecorder()
+	request, _ := http.NewRequest("HEAD", "test/url", nil)
+
+	creator.getHandler(config.Endpoint{File: "orders.json", Compression: "gzip"}, dir, &wrapper.FileOps{})(forced, request)
+	request.Header.Set("Accept-Encoding", "br")
+	creator.getHandler(config.Endpoint{File: "orders.json", Compression: CompressionAuto}, dir, &wrapper.FileOps{})(negotiated, request)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusOK, forced.Code)
+	assert.Equal("gzip", forced.Header().Get("Content-Encoding"))
+	assert.Empty(forced.Header().Get("Content-Length"))
+	assert.Empty(forced.Body.Bytes())
+	assert.Equal("br", negotiated.Header().Get("Content-Encoding"))
+	assert.Empty(negotiated.Body.Bytes())
+}
+
+func TestGetHandler_WritesNotModified_WhenCompressedResponseUnchanged(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	dir := writeTempFile(t, "orders.json", goodJSON)
+	defer os.RemoveAll(dir)
+	handler := creator.getHandler(config.Endpoint{File: "orders.json", Compression: CompressionAuto}, dir, &wrapper.FileOps{})
+	w := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "test/url", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	request.Header.Set("If-None-Match", "W/"+getETag(goodJSON))
+
+	handler(w, request)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusNotModified, w.Code)
+	assert.Empty(w.Body.Bytes())
+	assert.Empty(w.Header().Get("Content-Encoding"))
+}
+
+func TestGetHandler_ServesPrecompressedFile_WhenRequestAcceptsEncoding(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	writer.Write(goodJSON)
+	writer.Close()
+	dir := writeTempFile(t, "orders.json", goodJSON)
+	defer os.RemoveAll(dir)
+	writeTempFileIn(t, dir, "orders.json.gz", compressed.Bytes())
+	handler := creator.getHandler(config.Endpoint{File: "orders.json", EnforceValidJSON: true, Compression: CompressionPrecompressed}, dir, &wrapper.FileOps{})
+	accepted := httptest.NewRecorder()
+	identity := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "test/url", nil)
+
+	handler(identity, request)
+	request.Header.Set("Accept-Encoding", "br, gzip")
+	handler(accepted, request)
+
+	assert := assert.New(t)
+	assert.Equal(compressed.Bytes(), accepted.Body.Bytes())
+	assert.Equal("gzip", accepted.Header().Get("Content-Encoding"))
+	assert.Equal("application/json", accepted.Header().Get("Content-Type"))
+	assert.Equal(goodJSON, identity.Body.Bytes())
+	assert.Empty(identity.Header().Get("Content-Encoding"))
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"github.com/wcsanders1/MockApiHub/config"
+)
+
+const (
+	jsonContentType = "application/json"
+	xmlContentType  = "application/xml"
+)
+
+var contentTypes = map[string]string{
+	".json":     jsonContentType,
+	".xml":      xmlContentType,
+	".html":     "text/html; charset=utf-8",
+	".htm":      "text/html; charset=utf-8",
+	".csv":      "text/csv; charset=utf-8",
+	".txt":      "text/plain; charset=utf-8",
+	".png":      "image/png",
+	".pdf":      "application/pdf",
+	".pb":       "application/x-protobuf",
+	".protobuf": "application/x-protobuf",
+}
+
+// getContentType returns the content type of a data file, inferred from its extension, or an empty
+// string if it cannot be inferred.
+func getContentType(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if len(ext) == 0 {
+		return ""
+	}
+	if contentType, exists := contentTypes[ext]; exists {
+		return contentType
+	}
+	return mime.TypeByExtension(ext)
+}
+
+// withContentType returns the headers with a Content-Type header put first, so that a Content-Type
+// header among them still takes precedence. The content type configured takes precedence over the
+// one inferred; if neither is set, the headers are returned as they are.
+func withContentType(headers []config.Header, configured, inferred string) []config.Header {
+	contentType := configured
+	if len(contentType) == 0 {
+		contentType = inferred
+	}
+	if len(contentType) == 0 {
+		return headers
+	}
+	return append([]config.Header{{Key: "Content-Type", Value: contentType}}, headers...)
+}
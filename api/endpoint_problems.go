@@ -0,0 +1,56 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/route"
+)
+
+// FindEndpointProblems returns, keyed by endpoint name, a description of each endpoint of a mock API
+// that would not be registered, either because its query or request header matchers are invalid or
+// because another endpoint handles the same requests. Of two endpoints handling the same requests,
+// the one whose name sorts later is reported.
+func FindEndpointProblems(apiConfig *config.APIConfig) map[string]string {
+	problems := make(map[string]string)
+	routeTree := route.NewRouteTreeWithOptions(apiConfig.CaseSensitive, apiConfig.StrictTrailingSlash)
+	registered := make(map[string]map[string][]matchedHandler)
+	for _, name := range getSortedEndpointNames(apiConfig.Endpoints) {
+		endpoint := apiConfig.Endpoints[name]
+		matcher, err := newRequestMatcher(endpoint)
+		if err != nil {
+			problems[name] = err.Error()
+			continue
+		}
+
+		method := strings.ToUpper(endpoint.Method)
+		registeredRoute := ensureRouteRegistered(routeTree, getEndpointPath(apiConfig.BaseURL, endpoint), apiConfig.StrictTrailingSlash)
+		if len(registeredRoute) == 0 {
+			problems[name] = fmt.Sprintf("route %s cannot be registered", getEndpointPath(apiConfig.BaseURL, endpoint))
+			continue
+		}
+
+		if _, exists := registered[method]; !exists {
+			registered[method] = make(map[string][]matchedHandler)
+		}
+
+		conflict := ""
+		for _, existing := range registered[method][registeredRoute] {
+			if existing.matcher.hasSameConditions(matcher) {
+				conflict = existing.endpointName
+				break
+			}
+		}
+		if len(conflict) > 0 {
+			problems[name] = fmt.Sprintf("handles the same requests as endpoint %s (%s %s)", conflict, method, registeredRoute)
+			continue
+		}
+
+		registered[method][registeredRoute] = append(registered[method][registeredRoute], matchedHandler{
+			endpointName: name,
+			matcher:      matcher,
+		})
+	}
+	return problems
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/wcsanders1/MockApiHub/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindEndpointProblems_ReportsConflict_WhenEndpointsHandleSameRequests(t *testing.T) {
+	apiConfig := &config.APIConfig{
+		BaseURL: "customersApi",
+		Endpoints: map[string]config.Endpoint{
+			"getCustomer":      config.Endpoint{Path: "customers/:id", Method: "GET"},
+			"getCustomerAgain": config.Endpoint{Path: "Customers/:customerId", Method: "get"},
+			"getVipCustomer":   config.Endpoint{Path: "customers/:id", Method: "GET", Query: []config.Matcher{{Key: "vip"}}},
+			"deleteCustomer":   config.Endpoint{Path: "customers/:id", Method: "DELETE"},
+		},
+	}
+
+	result := FindEndpointProblems(apiConfig)
+
+	assert := assert.New(t)
+	assert.Equal(1, len(result))
+	assert.Contains(result["getCustomerAgain"], "getCustomer")
+}
+
+func TestFindEndpointProblems_ReportsInvalidMatcher_WhenRegexInvalid(t *testing.T) {
+	apiConfig := &config.APIConfig{
+		Endpoints: map[string]config.Endpoint{
+			"getCustomers": config.Endpoint{Path: "customers", Method: "GET", Query: []config.Matcher{{Key: "page", Regex: "("}}},
+		},
+	}
+
+	result := FindEndpointProblems(apiConfig)
+
+	assert.Contains(t, result, "getCustomers")
+}
+
+func TestFindEndpointProblems_ReturnsNoProblems_WhenCaseSensitiveRoutesDiffer(t *testing.T) {
+	apiConfig := &config.APIConfig{
+		CaseSensitive: true,
+		Endpoints: map[string]config.Endpoint{
+			"lower": config.Endpoint{Path: "customers", Method: "GET"},
+			"upper": config.Endpoint{Path: "Customers", Method: "GET"},
+		},
+	}
+
+	result := FindEndpointProblems(apiConfig)
+
+	assert.Empty(t, result)
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fileCache keeps the contents of the file an endpoint serves in memory, reading the file again
+// only when its modification time or size changes.
+type fileCache struct {
+	path     string
+	file     wrapper.IFileOps
+	validate func([]byte) error
+	log      *logrus.Entry
+	mutex    sync.Mutex
+	loaded   bool
+	modTime  time.Time
+	size     int64
+	content  []byte
+	etag     string
+	err      error
+}
+
+// newFileCache returns a cache of the file at path. If validate is not nil, contents it rejects are
+// not served.
+func newFileCache(path string, file wrapper.IFileOps, validate func([]byte) error, logger *logrus.Entry) *fileCache {
+	return &fileCache{
+		path:     path,
+		file:     file,
+		validate: validate,
+		log:      logger,
+	}
+}
+
+// get returns the contents of the file, their modification time and entity tag, or the error
+// reading or validating them, as of the last time the file changed.
+func (c *fileCache) get() ([]byte, time.Time, string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	fileInfo, err := c.file.Stat(c.path)
+	if err != nil {
+		c.loaded = false
+		return nil, time.Time{}, "", err
+	}
+
+	if c.loaded && fileInfo.ModTime().Equal(c.modTime) && fileInfo.Size() == c.size {
+		return c.content, c.modTime, c.etag, c.err
+	}
+
+	c.log.Debug("reading file into cache")
+	c.loaded = true
+	c.modTime = fileInfo.ModTime()
+	c.size = fileInfo.Size()
+	c.content, c.err = c.read()
+	c.etag = getETag(c.content)
+	return c.content, c.modTime, c.etag, c.err
+}
+
+func (c *fileCache) read() ([]byte, error) {
+	content, err := readFile(c.path, c.file)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.validate != nil {
+		if err := c.validate(content); err != nil {
+			return nil, err
+		}
+	}
+	return content, nil
+}
+
+func getCachedHandler(cache *fileCache, headers []config.Header, logger *logrus.Entry, allowCORS bool, statusCode int, cacheHeaders bool) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, header := range headers {
+			w.Header().Set(header.Key, header.Value)
+		}
+
+		if allowCORS {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		}
+
+		content, modTime, etag, err := cache.get()
+		if err != nil {
+			logger.WithError(err).Error("error serving cached file")
+			writeError(err, w)
+			return
+		}
+		if !cacheHeaders {
+			modTime, etag = time.Time{}, ""
+		}
+		logger.Debug("serving cached file")
+		serveContent(w, r, cache.path, modTime, etag, bytes.NewReader(content), statusCode)
+	}
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/fake"
+	"github.com/wcsanders1/MockApiHub/json"
+	"github.com/wcsanders1/MockApiHub/log"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func getFakeCachedFileOps(modTimes []time.Time, contents []byte) *wrapper.FakeFileOps {
+	fileOps := new(wrapper.FakeFileOps)
+	for _, modTime := range modTimes {
+		fileInfo := new(fake.FileInfo)
+		fileInfo.On("ModTime").Return(modTime)
+		fileInfo.On("Size").Return(int64(len(contents)))
+		fileOps.On("Stat", "test/fixture").Return(fileInfo, nil).Once()
+	}
+	fileOps.On("Open", "test/fixture").Return(os.NewFile(1, "fixture"), nil)
+	fileOps.On("ReadAll", mock.AnythingOfType("*os.File")).Return(contents, nil)
+	return fileOps
+}
+
+func TestGet_DoesNotReadFile_WhenFileUnchanged(t *testing.T) {
+	modTime := time.Now()
+	fileOps := getFakeCachedFileOps([]time.Time{modTime, modTime}, goodJSON)
+	cache := newFileCache("test/fixture", fileOps, json.ValidateJSON, log.GetFakeLogger())
+
+	cache.get()
+	result, resultModTime, _, err := cache.get()
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal(goodJSON, result)
+	assert.True(modTime.Equal(resultModTime))
+	fileOps.AssertNumberOfCalls(t, "Stat", 2)
+	fileOps.AssertNumberOfCalls(t, "ReadAll", 1)
+}
+
+func TestGet_ReadsFile_WhenFileChanged(t *testing.T) {
+	modTime := time.Now()
+	fileOps := getFakeCachedFileOps([]time.Time{modTime, modTime.Add(time.Second)}, goodJSON)
+	cache := newFileCache("test/fixture", fileOps, nil, log.GetFakeLogger())
+
+	cache.get()
+	cache.get()
+
+	fileOps.AssertNumberOfCalls(t, "ReadAll", 2)
+}
+
+func TestGet_ReturnsError_WhenCachedFileIsInvalidJSON(t *testing.T) {
+	modTime := time.Now()
+	fileOps := getFakeCachedFileOps([]time.Time{modTime, modTime}, []byte(`{"id": `))
+	cache := newFileCache("test/fixture", fileOps, json.ValidateJSON, log.GetFakeLogger())
+
+	cache.get()
+	result, _, _, err := cache.get()
+
+	assert := assert.New(t)
+	assert.Error(err)
+	assert.Nil(result)
+	fileOps.AssertNumberOfCalls(t, "ReadAll", 1)
+}
+
+func TestGet_ReturnsError_WhenStatFails(t *testing.T) {
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("Stat", "test/fixture").Return(new(fake.FileInfo), errors.New("not found"))
+	cache := newFileCache("test/fixture", fileOps, nil, log.GetFakeLogger())
+
+	result, _, _, err := cache.get()
+
+	assert := assert.New(t)
+	assert.Error(err)
+	assert.Nil(result)
+	fileOps.AssertNotCalled(t, "Open", mock.Anything)
+}
+
+func TestGetHandler_ServesChangedFile_WhenCacheFileSet(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	dir := writeTempFile(t, "orders.json", []byte(`[]`))
+	defer os.RemoveAll(dir)
+	handler := creator.getHandler(config.Endpoint{File: "orders.json", EnforceValidJSON: true, CacheFile: true}, dir, &wrapper.FileOps{})
+	request, _ := http.NewRequest("GET", "test/url", nil)
+	first := httptest.NewRecorder()
+	second := httptest.NewRecorder()
+
+	handler(first, request)
+	if err := ioutil.WriteFile(getFilePath(dir, "orders.json"), []byte(`[{"id": 1}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	handler(second, request)
+
+	assert := assert.New(t)
+	assert.Equal("[]", first.Body.String())
+	assert.Equal(`[{"id": 1}]`, second.Body.String())
+	assert.Equal("application/json", second.Header().Get("Content-Type"))
+}
+
+func TestGetHandler_WritesNotModified_WhenCachedFileUnchanged(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	dir := writeTempFile(t, "orders.json", goodJSON)
+	defer os.RemoveAll(dir)
+	handler := creator.getHandler(config.Endpoint{File: "orders.json", CacheFile: true}, dir, &wrapper.FileOps{})
+	w := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "test/url", nil)
+	request.Header.Set("If-None-Match", getETag(goodJSON))
+
+	handler(w, request)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+}
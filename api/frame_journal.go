@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxJournalFrames is the number of frames the journal of a WebSocket endpoint keeps; once it is
+// full, the oldest frame is dropped for each frame received.
+const maxJournalFrames = 100
+
+type (
+	// ReceivedFrame is a frame a client sent to a WebSocket endpoint.
+	ReceivedFrame struct {
+		Time time.Time
+		Data string
+	}
+
+	// frameJournal records the frames clients send to a WebSocket endpoint so that tests can ask the
+	// hub what their code sent.
+	frameJournal struct {
+		mutex  sync.Mutex
+		frames []ReceivedFrame
+	}
+
+	frameJournalKey struct{}
+)
+
+func (j *frameJournal) record(data []byte) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if len(j.frames) == maxJournalFrames {
+		j.frames = append(j.frames[:0], j.frames[1:]...)
+	}
+	j.frames = append(j.frames, ReceivedFrame{Time: time.Now(), Data: string(data)})
+}
+
+func (j *frameJournal) getFrames() []ReceivedFrame {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	return append([]ReceivedFrame{}, j.frames...)
+}
+
+// wrap makes the journal available to next, which records the frames it receives in the journal
+// returned by getFrameJournal.
+func (j *frameJournal) wrap(next func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		next(w, r.WithContext(context.WithValue(r.Context(), frameJournalKey{}, j)))
+	}
+}
+
+// getFrameJournal returns the journal of the endpoint handling the request, or nil if it has none.
+func getFrameJournal(r *http.Request) *frameJournal {
+	journal, _ := r.Context().Value(frameJournalKey{}).(*frameJournal)
+	return journal
+}
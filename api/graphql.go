@@ -0,0 +1,125 @@
+package api
+
+import (
+	encodingJSON "encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/graphql"
+	"github.com/wcsanders1/MockApiHub/json"
+	"github.com/wcsanders1/MockApiHub/log"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// EndpointTypeGraphQL is the type of endpoints that resolve GraphQL requests from fixtures.
+const EndpointTypeGraphQL = "graphql"
+
+// getGraphQLHandler returns a handler that resolves GraphQL requests, sent as JSON, as a query in the
+// query string or as an application/graphql body, against the endpoint's schema. The schema and
+// fixtures are read on every request, so they can be changed without reloading the mock API.
+func (c creator) getGraphQLHandler(endpoint config.Endpoint, dir string, file wrapper.IFileOps) func(w http.ResponseWriter, r *http.Request) {
+	schemaPath := getFilePath(dir, endpoint.GraphQL.Schema)
+	fixturesPath := getFilePath(dir, endpoint.GraphQL.Fixtures)
+	contextLogger := c.log.WithFields(logrus.Fields{
+		log.FuncField:   "GraphQL handler for mock API",
+		log.SchemaField: schemaPath,
+	})
+
+	if endpoint.ExpandEnv {
+		file = &envFileOps{file}
+	}
+
+	headers := withContentType(endpoint.Headers, endpoint.ContentType, jsonContentType)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, header := range headers {
+			w.Header().Set(header.Key, header.Value)
+		}
+
+		if endpoint.AllowCORS {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		}
+
+		schemaContents, err := readFile(schemaPath, file)
+		if err != nil {
+			contextLogger.WithError(err).Error("error reading GraphQL schema")
+			writeError(err, w)
+			return
+		}
+		schema, err := graphql.LoadSchema(schemaPath, string(schemaContents))
+		if err != nil {
+			contextLogger.WithError(err).Error("error parsing GraphQL schema")
+			writeError(err, w)
+			return
+		}
+
+		var fixtures graphql.Fixtures
+		if len(fixturesPath) > 0 {
+			contents, err := json.GetJSON(fixturesPath, file)
+			if err == nil {
+				err = encodingJSON.Unmarshal(contents, &fixtures)
+			}
+			if err != nil {
+				contextLogger.WithError(err).Error("error reading GraphQL fixtures")
+				writeError(err, w)
+				return
+			}
+		}
+
+		statusCode := endpoint.HTTPStatusCode
+		var response interface{}
+		request, err := readGraphQLRequest(r)
+		if err != nil {
+			contextLogger.WithError(err).Debug("invalid GraphQL request")
+			statusCode = http.StatusBadRequest
+			response = graphql.Response{Errors: gqlerror.List{{Message: err.Error()}}}
+		} else {
+			response = graphql.Execute(schema, fixtures, request)
+		}
+
+		body, err := encodingJSON.Marshal(response)
+		if err != nil {
+			contextLogger.WithError(err).Error("error writing GraphQL response")
+			writeError(err, w)
+			return
+		}
+		writeStatusCode(statusCode, w)
+		w.Write(body)
+	}
+}
+
+func readGraphQLRequest(r *http.Request) (graphql.Request, error) {
+	var request graphql.Request
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		request.Query = query.Get("query")
+		request.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); len(variables) > 0 {
+			if err := encodingJSON.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				return request, fmt.Errorf("variables must be a JSON object: %v", err)
+			}
+		}
+	} else {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return request, err
+		}
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/graphql") {
+			request.Query = string(body)
+		} else if err := encodingJSON.Unmarshal(body, &request); err != nil {
+			return request, fmt.Errorf("request body must be a JSON object: %v", err)
+		}
+	}
+
+	if len(request.Query) == 0 {
+		return request, errors.New("request has no query")
+	}
+	return request, nil
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/log"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	graphQLSchema = []byte(`type Query {
+	order(id: ID!): Order
+}
+
+type Order {
+	id: ID!
+	total: Float!
+}`)
+
+	graphQLFixtures = []byte(`{
+	"operations": {
+		"GetOrder": {"order": {"id": "7", "total": 9.5}}
+	}
+}`)
+)
+
+func getGraphQLResponse(t *testing.T, request *http.Request) *httptest.ResponseRecorder {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	dir := writeTempFile(t, "schema.graphql", graphQLSchema)
+	defer os.RemoveAll(dir)
+	writeTempFileIn(t, dir, "fixtures.json", graphQLFixtures)
+	endpoint := config.Endpoint{
+		Type:    EndpointTypeGraphQL,
+		GraphQL: config.GraphQL{Schema: "schema.graphql", Fixtures: "fixtures.json"},
+	}
+	w := httptest.NewRecorder()
+
+	creator.getHandler(endpoint, dir, &wrapper.FileOps{})(w, request)
+	return w
+}
+
+func TestGetHandler_ResolvesOperationFixture_WhenGraphQLPosted(t *testing.T) {
+	body := `{"query": "query GetOrder { order(id: \"7\") { id total } }", "operationName": "GetOrder"}`
+	request, _ := http.NewRequest("POST", "test/url", strings.NewReader(body))
+
+	w := getGraphQLResponse(t, request)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Equal("application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(`{"data": {"order": {"id": "7", "total": 9.5}}}`, w.Body.String())
+}
+
+func TestGetHandler_ResolvesQueryString_WhenGraphQLRequestedWithGet(t *testing.T) {
+	query := url.Values{"query": {"query Find($id: ID!) { order(id: $id) { id } }"}, "variables": {`{"id": "3"}`}}
+	request, _ := http.NewRequest("GET", "test/url?"+query.Encode(), nil)
+
+	w := getGraphQLResponse(t, request)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusOK, w.Code)
+	assert.JSONEq(`{"data": {"order": {"id": "1"}}}`, w.Body.String())
+}
+
+func TestGetHandler_ReturnsErrors_WhenGraphQLQueryInvalid(t *testing.T) {
+	request, _ := http.NewRequest("POST", "test/url", strings.NewReader("{ order(id: 1) { missing } }"))
+	request.Header.Set("Content-Type", "application/graphql")
+
+	w := getGraphQLResponse(t, request)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Contains(w.Body.String(), `"errors"`)
+	assert.Contains(w.Body.String(), `Cannot query field \"missing\" on type \"Order\".`)
+	assert.NotContains(w.Body.String(), `"data"`)
+}
+
+func TestGetHandler_ReturnsBadRequest_WhenGraphQLRequestMalformed(t *testing.T) {
+	request, _ := http.NewRequest("POST", "test/url", strings.NewReader("not json"))
+
+	w := getGraphQLResponse(t, request)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusBadRequest, w.Code)
+	assert.Contains(w.Body.String(), `"errors"`)
+}
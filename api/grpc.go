@@ -0,0 +1,331 @@
+package api
+
+import (
+	encodingJSON "encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/log"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// ProtocolGRPC is the protocol of mock APIs that answer the gRPC methods of a descriptor set.
+const ProtocolGRPC = "grpc"
+
+// grpcMethod is a gRPC method a mock API answers, and the endpoint that answers it.
+type grpcMethod struct {
+	endpointName string
+	endpoint     config.Endpoint
+	descriptor   protoreflect.MethodDescriptor
+}
+
+// IsProtocol reports whether a mock API can use the protocol provided. An empty protocol is HTTP.
+func IsProtocol(protocol string) bool {
+	switch strings.ToLower(protocol) {
+	case "", "http", ProtocolGRPC:
+		return true
+	}
+	return false
+}
+
+// LoadDescriptorSet parses a compiled FileDescriptorSet, such as protoc writes with
+// --descriptor_set_out and --include_imports.
+func LoadDescriptorSet(contents []byte) (*protoregistry.Files, error) {
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(contents, &set); err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %v", err)
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %v", err)
+	}
+	return files, nil
+}
+
+// FindGRPCMethod returns the method of the descriptor set named by path, such as
+// orders.OrderService/GetOrder.
+func FindGRPCMethod(files *protoregistry.Files, path string) (protoreflect.MethodDescriptor, error) {
+	path = strings.TrimPrefix(path, "/")
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return nil, fmt.Errorf("gRPC method is not of the form service/method: %s", path)
+	}
+
+	serviceName, methodName := path[:i], path[i+1:]
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, fmt.Errorf("service not found in descriptor set: %s", serviceName)
+	}
+	service, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("not a service: %s", serviceName)
+	}
+
+	method := service.Methods().ByName(protoreflect.Name(methodName))
+	if method == nil {
+		return nil, fmt.Errorf("method %s not found in service %s", methodName, serviceName)
+	}
+	return method, nil
+}
+
+// FindGRPCProblems returns the ways in which an endpoint of a gRPC mock API is misconfigured, given
+// the mock API's descriptor set and the contents of the endpoint's file, which are nil if it has none.
+func FindGRPCProblems(files *protoregistry.Files, endpoint config.Endpoint, fixture []byte) []string {
+	var problems []string
+	if _, err := parseGRPCCode(endpoint.GRPC.Status); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if _, err := parseDuration(endpoint.GRPC.Delay); err != nil {
+		problems = append(problems, fmt.Sprintf("invalid delay: %v", err))
+	}
+
+	method, err := FindGRPCMethod(files, endpoint.Path)
+	if err != nil {
+		return append(problems, err.Error())
+	}
+
+	if fixture == nil {
+		fixture, err = getInlineGRPCFixture(endpoint)
+		if err != nil {
+			return append(problems, err.Error())
+		}
+	}
+	if _, err := getGRPCMessages(method, fixture); err != nil {
+		problems = append(problems, err.Error())
+	}
+	return problems
+}
+
+// registerGRPCMethods loads the mock API's descriptor set from dir and creates the gRPC server that
+// answers its methods. Endpoints naming methods that are not in the descriptor set, or methods
+// another endpoint answers, are not registered; of two endpoints answering the same method, the one
+// whose name sorts later is dropped.
+func (api *API) registerGRPCMethods(dir string) error {
+	contents, err := readFile(getFilePath(dir, api.descriptorSet), api.file)
+	if err != nil {
+		return err
+	}
+	files, err := LoadDescriptorSet(contents)
+	if err != nil {
+		return err
+	}
+
+	methods := make(map[string]grpcMethod)
+	for _, endpointName := range getSortedEndpointNames(api.endpoints) {
+		endpoint := api.endpoints[endpointName]
+		contextLoggerEndpoint := api.log.WithFields(logrus.Fields{
+			log.PathField:         endpoint.Path,
+			log.FileField:         endpoint.File,
+			log.EndpointNameField: endpointName,
+		})
+
+		contextLoggerEndpoint.Debug("registering gRPC method")
+		descriptor, err := FindGRPCMethod(files, endpoint.Path)
+		if err == nil {
+			_, err = parseGRPCCode(endpoint.GRPC.Status)
+		}
+		if err == nil {
+			_, err = parseDuration(endpoint.GRPC.Delay)
+		}
+		if err != nil {
+			contextLoggerEndpoint.WithError(err).Error("invalid gRPC endpoint, moving on to next endpoint...")
+			delete(api.endpoints, endpointName)
+			continue
+		}
+
+		fullMethod := "/" + strings.TrimPrefix(endpoint.Path, "/")
+		if _, exists := methods[fullMethod]; exists {
+			contextLoggerEndpoint.Warn("gRPC method already answered by another endpoint, moving on to next endpoint...")
+			delete(api.endpoints, endpointName)
+			continue
+		}
+
+		methods[fullMethod] = grpcMethod{
+			endpointName: endpointName,
+			endpoint:     endpoint,
+			descriptor:   descriptor,
+		}
+	}
+
+	api.grpcServer = grpc.NewServer(grpc.UnknownServiceHandler(getGRPCHandler(methods, dir, api.file, api.log)))
+	return nil
+}
+
+// getGRPCHandler returns a handler that answers calls to the methods provided, keyed by their full
+// names, from the fixtures of their endpoints. Fixtures are read on every call. Every message a client
+// sends is logged; client-streaming methods answer once the client has finished sending.
+func getGRPCHandler(methods map[string]grpcMethod, dir string, file wrapper.IFileOps, logger *logrus.Entry) grpc.StreamHandler {
+	return func(srv interface{}, stream grpc.ServerStream) error {
+		fullMethod, _ := grpc.MethodFromServerStream(stream)
+		contextLogger := logger.WithFields(logrus.Fields{
+			log.FuncField:   "gRPC handler for mock API",
+			log.MethodField: fullMethod,
+		})
+
+		method, exists := methods[fullMethod]
+		if !exists {
+			contextLogger.Warn("no endpoint answers this gRPC method")
+			return status.Errorf(codes.Unimplemented, "no endpoint answers method %s", fullMethod)
+		}
+		contextLogger = contextLogger.WithField(log.EndpointNameField, method.endpointName)
+
+		for {
+			request := dynamicpb.NewMessage(method.descriptor.Input())
+			if err := stream.RecvMsg(request); err == io.EOF {
+				break
+			} else if err != nil {
+				contextLogger.WithError(err).Debug("error receiving gRPC message")
+				return err
+			}
+
+			contextLogger.WithField(log.GRPCMessageField, protojson.Format(request)).Info("received gRPC message")
+			if !method.descriptor.IsStreamingClient() {
+				break
+			}
+		}
+
+		endpoint := method.endpoint
+		stream.SetTrailer(getMetadata(endpoint.GRPC.Trailers))
+		if err := stream.SetHeader(getMetadata(endpoint.Headers)); err != nil {
+			return err
+		}
+
+		code, _ := parseGRPCCode(endpoint.GRPC.Status)
+		if code != codes.OK && !method.descriptor.IsStreamingServer() {
+			return status.Error(code, endpoint.GRPC.Message)
+		}
+
+		messages, err := readGRPCMessages(method, dir, file)
+		if err != nil {
+			contextLogger.WithError(err).Error("error reading gRPC fixture")
+			return status.Errorf(codes.Internal, "error reading fixture: %v", err)
+		}
+
+		delay, _ := parseDuration(endpoint.GRPC.Delay)
+		for _, message := range messages {
+			if method.descriptor.IsStreamingServer() && delay > 0 {
+				select {
+				case <-time.After(delay):
+				case <-stream.Context().Done():
+					return stream.Context().Err()
+				}
+			}
+			if err := stream.SendMsg(message); err != nil {
+				contextLogger.WithError(err).Debug("error sending gRPC message")
+				return err
+			}
+		}
+
+		if code != codes.OK {
+			return status.Error(code, endpoint.GRPC.Message)
+		}
+		return nil
+	}
+}
+
+func readGRPCMessages(method grpcMethod, dir string, file wrapper.IFileOps) ([]proto.Message, error) {
+	endpoint := method.endpoint
+	if len(endpoint.File) == 0 {
+		fixture, err := getInlineGRPCFixture(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		return getGRPCMessages(method.descriptor, fixture)
+	}
+
+	if endpoint.ExpandEnv {
+		file = &envFileOps{file}
+	}
+	fixture, err := readFile(getFilePath(dir, endpoint.File), file)
+	if err != nil {
+		return nil, err
+	}
+	return getGRPCMessages(method.descriptor, fixture)
+}
+
+func getInlineGRPCFixture(endpoint config.Endpoint) ([]byte, error) {
+	if endpoint.JSON != nil {
+		return encodingJSON.Marshal(endpoint.JSON)
+	}
+	if len(endpoint.Body) > 0 {
+		return []byte(endpoint.Body), nil
+	}
+	return nil, nil
+}
+
+// getGRPCMessages converts a fixture, written in the JSON mapping of protocol buffers, to the messages
+// a method returns: a JSON array holds the messages of a server-streaming method, and a JSON object
+// the message of any other method. A method without a fixture returns an empty message, or, if it
+// is server-streaming, none.
+func getGRPCMessages(method protoreflect.MethodDescriptor, fixture []byte) ([]proto.Message, error) {
+	var raw []encodingJSON.RawMessage
+	switch {
+	case len(fixture) == 0 && method.IsStreamingServer():
+		return nil, nil
+	case len(fixture) == 0:
+		raw = []encodingJSON.RawMessage{encodingJSON.RawMessage("{}")}
+	case method.IsStreamingServer():
+		if err := encodingJSON.Unmarshal(fixture, &raw); err != nil {
+			return nil, fmt.Errorf("fixture of server-streaming method %s is not a JSON array: %v", method.FullName(), err)
+		}
+	default:
+		raw = []encodingJSON.RawMessage{fixture}
+	}
+
+	messages := make([]proto.Message, 0, len(raw))
+	for i, contents := range raw {
+		message := dynamicpb.NewMessage(method.Output())
+		if err := protojson.Unmarshal(contents, message); err != nil {
+			if method.IsStreamingServer() {
+				return nil, fmt.Errorf("message %d of fixture is not a valid %s: %v", i+1, method.Output().FullName(), err)
+			}
+			return nil, fmt.Errorf("fixture is not a valid %s: %v", method.Output().FullName(), err)
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// parseGRPCCode parses the name of a gRPC status code, such as NOT_FOUND, or its number. An empty
+// status is OK.
+func parseGRPCCode(name string) (codes.Code, error) {
+	if len(name) == 0 {
+		return codes.OK, nil
+	}
+
+	value := []byte(name)
+	if name[0] < '0' || name[0] > '9' {
+		value = []byte(fmt.Sprintf("%q", strings.ToUpper(name)))
+	}
+
+	var code codes.Code
+	if err := code.UnmarshalJSON(value); err != nil {
+		return codes.OK, fmt.Errorf("invalid gRPC status: %s", name)
+	}
+	return code, nil
+}
+
+func getMetadata(headers []config.Header) metadata.MD {
+	md := metadata.MD{}
+	for _, header := range headers {
+		md.Append(header.Key, header.Value)
+	}
+	return md
+}
@@ -0,0 +1,265 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/wcsanders1/MockApiHub/config"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func getOrdersDescriptorSet(t *testing.T) []byte {
+	field := func(name string, number int32, fieldType descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Type:   fieldType.Enum(),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		}
+	}
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("orders.proto"),
+		Package: proto.String("orders"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("OrderRequest"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+			}},
+			{Name: proto.String("Order"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+				field("quantity", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32),
+			}},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("OrderService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("GetOrder"), InputType: proto.String(".orders.OrderRequest"), OutputType: proto.String(".orders.Order")},
+				{Name: proto.String("WatchOrder"), InputType: proto.String(".orders.OrderRequest"), OutputType: proto.String(".orders.Order"), ServerStreaming: proto.Bool(true)},
+			},
+		}},
+	}
+
+	contents, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{file}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return contents
+}
+
+func getOrdersMethod(t *testing.T, name string) protoreflect.MethodDescriptor {
+	files, err := LoadDescriptorSet(getOrdersDescriptorSet(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	method, err := FindGRPCMethod(files, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return method
+}
+
+func startGRPCAPI(t *testing.T, endpoints map[string]config.Endpoint) (*API, *grpc.ClientConn, func()) {
+	dir := writeTempFile(t, "orders.protoset", getOrdersDescriptorSet(t))
+	writeTempFileIn(t, dir, "order.json", []byte(`{"id": "7", "quantity": 3}`))
+	writeTempFileIn(t, dir, "orders.json", []byte(`[{"id": "7", "quantity": 3}, {"id": "7", "quantity": 4}]`))
+
+	api, err := NewAPI(&config.APIConfig{
+		Protocol:      ProtocolGRPC,
+		DescriptorSet: "orders.protoset",
+		Endpoints:     endpoints,
+	})
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	if err := api.Start(dir, "", ""); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	conn, err := grpc.NewClient(fmt.Sprintf("localhost:%d", api.GetPort()), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		api.Shutdown()
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return api, conn, func() {
+		conn.Close()
+		api.Shutdown()
+		os.RemoveAll(dir)
+	}
+}
+
+func invokeGetOrder(t *testing.T, conn *grpc.ClientConn, opts ...grpc.CallOption) (string, error) {
+	method := getOrdersMethod(t, "orders.OrderService/GetOrder")
+	request := dynamicpb.NewMessage(method.Input())
+	request.Set(method.Input().Fields().ByName("id"), protoreflect.ValueOfString("7"))
+	response := dynamicpb.NewMessage(method.Output())
+
+	err := conn.Invoke(context.Background(), "/orders.OrderService/GetOrder", request, response, opts...)
+	return protojson.Format(response), err
+}
+
+func TestStart_AnswersUnaryGRPCMethod_WhenFixtureProvided(t *testing.T) {
+	_, conn, stop := startGRPCAPI(t, map[string]config.Endpoint{
+		"getOrder": {
+			Path:    "orders.OrderService/GetOrder",
+			File:    "order.json",
+			Headers: []config.Header{{Key: "X-Source", Value: "mock"}},
+		},
+	})
+	defer stop()
+	var header metadata.MD
+
+	response, err := invokeGetOrder(t, conn, grpc.Header(&header))
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.JSONEq(`{"id": "7", "quantity": 3}`, response)
+	assert.Equal([]string{"mock"}, header.Get("x-source"))
+}
+
+func TestStart_ReturnsGRPCStatus_WhenStatusConfigured(t *testing.T) {
+	_, conn, stop := startGRPCAPI(t, map[string]config.Endpoint{
+		"getOrder": {
+			Path: "/orders.OrderService/GetOrder",
+			GRPC: config.GRPC{
+				Status:   "not_found",
+				Message:  "no such order",
+				Trailers: []config.Header{{Key: "retry-after", Value: "5"}},
+			},
+		},
+	})
+	defer stop()
+	var trailer metadata.MD
+
+	_, err := invokeGetOrder(t, conn, grpc.Trailer(&trailer))
+
+	assert := assert.New(t)
+	assert.Equal(codes.NotFound, status.Code(err))
+	assert.Equal("no such order", status.Convert(err).Message())
+	assert.Equal([]string{"5"}, trailer.Get("retry-after"))
+}
+
+func TestStart_StreamsGRPCMessages_WhenMethodServerStreaming(t *testing.T) {
+	_, conn, stop := startGRPCAPI(t, map[string]config.Endpoint{
+		"watchOrder": {
+			Path: "orders.OrderService/WatchOrder",
+			File: "orders.json",
+			GRPC: config.GRPC{Status: "UNAVAILABLE", Delay: "1ms"},
+		},
+	})
+	defer stop()
+	method := getOrdersMethod(t, "orders.OrderService/WatchOrder")
+	stream, err := conn.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, "/orders.OrderService/WatchOrder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream.SendMsg(dynamicpb.NewMessage(method.Input()))
+	stream.CloseSend()
+
+	var quantities []int64
+	for {
+		response := dynamicpb.NewMessage(method.Output())
+		if err = stream.RecvMsg(response); err != nil {
+			break
+		}
+		quantities = append(quantities, response.Get(method.Output().Fields().ByName("quantity")).Int())
+	}
+
+	assert := assert.New(t)
+	assert.Equal([]int64{3, 4}, quantities)
+	assert.Equal(codes.Unavailable, status.Code(err))
+	assert.NotEqual(io.EOF, err)
+}
+
+func TestStart_ReturnsUnimplemented_WhenNoEndpointAnswersMethod(t *testing.T) {
+	_, conn, stop := startGRPCAPI(t, map[string]config.Endpoint{})
+	defer stop()
+
+	_, err := invokeGetOrder(t, conn)
+
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestStart_DropsGRPCEndpoint_WhenMethodNotInDescriptorSet(t *testing.T) {
+	api, _, stop := startGRPCAPI(t, map[string]config.Endpoint{
+		"getOrder":    {Path: "orders.OrderService/GetOrder"},
+		"cancelOrder": {Path: "orders.OrderService/CancelOrder"},
+	})
+	defer stop()
+
+	result := api.GetEndpoints()
+
+	assert := assert.New(t)
+	assert.Contains(result, "getOrder")
+	assert.NotContains(result, "cancelOrder")
+}
+
+func TestNewAPI_ReturnsError_WhenGRPCAPIShared(t *testing.T) {
+	result, err := NewAPI(&config.APIConfig{
+		Protocol: ProtocolGRPC,
+		HTTP:     config.HTTP{Port: 5000, Shared: true},
+	})
+
+	assert := assert.New(t)
+	assert.Nil(result)
+	assert.Error(err)
+}
+
+func TestFindGRPCProblems_ReturnsProblems_WhenEndpointInvalid(t *testing.T) {
+	files, _ := LoadDescriptorSet(getOrdersDescriptorSet(t))
+	endpoint := config.Endpoint{
+		Path: "orders.OrderService/WatchOrder",
+		GRPC: config.GRPC{Status: "BROKEN", Delay: "soon"},
+	}
+
+	result := FindGRPCProblems(files, endpoint, []byte(`{"id": "7"}`))
+
+	assert := assert.New(t)
+	assert.Equal(3, len(result))
+	assert.Equal("invalid gRPC status: BROKEN", result[0])
+	assert.Contains(result[2], "fixture of server-streaming method orders.OrderService.WatchOrder is not a JSON array")
+}
+
+func TestFindGRPCProblems_ReturnsProblem_WhenMethodNotFound(t *testing.T) {
+	files, _ := LoadDescriptorSet(getOrdersDescriptorSet(t))
+
+	result := FindGRPCProblems(files, config.Endpoint{Path: "orders.OrderService/CancelOrder"}, nil)
+
+	assert.Equal(t, []string{"method CancelOrder not found in service orders.OrderService"}, result)
+}
+
+func TestGetGRPCMessages_ReturnsError_WhenFixtureHasUnknownField(t *testing.T) {
+	method := getOrdersMethod(t, "orders.OrderService/GetOrder")
+
+	_, err := getGRPCMessages(method, []byte(`{"total": 9}`))
+
+	assert.Error(t, err)
+}
+
+func TestParseGRPCCode_ParsesNamesAndNumbers(t *testing.T) {
+	assert := assert.New(t)
+	for name, expected := range map[string]codes.Code{"": codes.OK, "NOT_FOUND": codes.NotFound, "permission_denied": codes.PermissionDenied, "14": codes.Unavailable} {
+		result, err := parseGRPCCode(name)
+		assert.NoError(err)
+		assert.Equal(expected, result)
+	}
+
+	_, err := parseGRPCCode("17")
+	assert.Error(err)
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/xml"
+
+	"github.com/antchfx/xpath"
+)
+
+type (
+	requestMatcher struct {
+		query      []config.Matcher
+		headers    []config.Matcher
+		xpath      []config.Matcher
+		soapAction string
+		regexes    map[string]*regexp.Regexp
+		xpaths     map[string]*xpath.Expr
+	}
+
+	matchedHandler struct {
+		endpointName string
+		matcher      *requestMatcher
+		handler      func(http.ResponseWriter, *http.Request)
+	}
+)
+
+func newRequestMatcher(endpoint config.Endpoint) (*requestMatcher, error) {
+	matcher := &requestMatcher{
+		query:      endpoint.Query,
+		headers:    endpoint.RequestHeaders,
+		xpath:      endpoint.XPath,
+		soapAction: endpoint.SOAPAction,
+		regexes:    make(map[string]*regexp.Regexp),
+		xpaths:     make(map[string]*xpath.Expr),
+	}
+
+	for _, m := range append(append(append([]config.Matcher{}, endpoint.Query...), endpoint.RequestHeaders...), endpoint.XPath...) {
+		if len(m.Key) == 0 {
+			return nil, fmt.Errorf("matcher has no key: %+v", m)
+		}
+
+		if len(m.Regex) == 0 {
+			continue
+		}
+
+		regex, err := regexp.Compile(m.Regex)
+		if err != nil {
+			return nil, err
+		}
+		matcher.regexes[m.Regex] = regex
+	}
+
+	for _, m := range endpoint.XPath {
+		expr, err := xml.CompilePath(m.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid XPath expression %s: %v", m.Key, err)
+		}
+		matcher.xpaths[m.Key] = expr
+	}
+
+	return matcher, nil
+}
+
+func (m *requestMatcher) matches(r *http.Request) bool {
+	query := r.URL.Query()
+	for _, queryMatcher := range m.query {
+		if !m.valuesMatch(queryMatcher, query[queryMatcher.Key]) {
+			return false
+		}
+	}
+
+	for _, headerMatcher := range m.headers {
+		if !m.valuesMatch(headerMatcher, r.Header[http.CanonicalHeaderKey(headerMatcher.Key)]) {
+			return false
+		}
+	}
+
+	if len(m.soapAction) > 0 && getSOAPAction(r) != m.soapAction {
+		return false
+	}
+
+	if len(m.xpath) > 0 {
+		return m.bodyMatches(r)
+	}
+
+	return true
+}
+
+// bodyMatches reports whether the request's body is XML satisfying the XPath matchers. The body is
+// restored so that it can be read again.
+func (m *requestMatcher) bodyMatches(r *http.Request) bool {
+	if r.Body == nil {
+		return false
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+
+	document, err := xml.Parse(body)
+	if err != nil {
+		return false
+	}
+
+	for _, xpathMatcher := range m.xpath {
+		var values []string
+		if value, found := xml.Lookup(document, m.xpaths[xpathMatcher.Key]); found {
+			values = []string{value}
+		}
+		if !m.valuesMatch(xpathMatcher, values) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getSOAPAction returns the action of a SOAP request: the SOAPAction header of SOAP 1.1, without its
+// quotes, or the action parameter of the content type of SOAP 1.2.
+func getSOAPAction(r *http.Request) string {
+	if action := r.Header.Get("SOAPAction"); len(action) > 0 {
+		return strings.Trim(action, `"`)
+	}
+
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return params["action"]
+}
+
+func (m *requestMatcher) valuesMatch(matcher config.Matcher, values []string) bool {
+	if matcher.Absent {
+		return len(values) == 0
+	}
+
+	if len(values) == 0 {
+		return false
+	}
+
+	if len(matcher.Value) == 0 && len(matcher.Regex) == 0 {
+		return true
+	}
+
+	for _, value := range values {
+		if len(matcher.Value) > 0 && value != matcher.Value {
+			continue
+		}
+
+		if len(matcher.Regex) > 0 && !m.regexes[matcher.Regex].MatchString(value) {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// specificity is the number of conditions a request must satisfy; the handlers with the most
+// conditions are tried first so that an endpoint without conditions acts as a fallback.
+func (m *requestMatcher) specificity() int {
+	specificity := len(m.query) + len(m.headers) + len(m.xpath)
+	if len(m.soapAction) > 0 {
+		specificity++
+	}
+	return specificity
+}
+
+func (m *requestMatcher) hasSameConditions(other *requestMatcher) bool {
+	return haveSameMatchers(m.query, other.query) && haveSameMatchers(m.headers, other.headers) &&
+		haveSameMatchers(m.xpath, other.xpath) && m.soapAction == other.soapAction
+}
+
+// haveSameMatchers compares two lists of matchers as sets, since the order in which
+// conditions are listed does not change which requests they match.
+func haveSameMatchers(matchers, others []config.Matcher) bool {
+	if len(matchers) != len(others) {
+		return false
+	}
+
+	return reflect.DeepEqual(sortMatchers(matchers), sortMatchers(others))
+}
+
+func sortMatchers(matchers []config.Matcher) []config.Matcher {
+	sorted := append([]config.Matcher{}, matchers...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return fmt.Sprintf("%+v", sorted[i]) < fmt.Sprintf("%+v", sorted[j])
+	})
+	return sorted
+}
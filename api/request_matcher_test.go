@@ -0,0 +1,212 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/wcsanders1/MockApiHub/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRequestMatcher_ReturnsError_WhenRegexInvalid(t *testing.T) {
+	endpoint := config.Endpoint{
+		Query: []config.Matcher{
+			config.Matcher{Key: "status", Regex: "("},
+		},
+	}
+
+	result, err := newRequestMatcher(endpoint)
+
+	assert := assert.New(t)
+	assert.Error(err)
+	assert.Nil(result)
+}
+
+func TestNewRequestMatcher_ReturnsError_WhenKeyMissing(t *testing.T) {
+	endpoint := config.Endpoint{
+		RequestHeaders: []config.Matcher{
+			config.Matcher{Value: "application/xml"},
+		},
+	}
+
+	result, err := newRequestMatcher(endpoint)
+
+	assert := assert.New(t)
+	assert.Error(err)
+	assert.Nil(result)
+}
+
+func TestMatches_ReturnsTrue_WhenNoConditions(t *testing.T) {
+	matcher, _ := newRequestMatcher(config.Endpoint{})
+	request, _ := http.NewRequest("GET", "accounts?status=closed", nil)
+
+	assert.True(t, matcher.matches(request))
+}
+
+func TestMatches_MatchesQueryValueExactly(t *testing.T) {
+	matcher, _ := newRequestMatcher(config.Endpoint{
+		Query: []config.Matcher{
+			config.Matcher{Key: "status", Value: "closed"},
+		},
+	})
+	closedRequest, _ := http.NewRequest("GET", "accounts?status=closed", nil)
+	openRequest, _ := http.NewRequest("GET", "accounts?status=open", nil)
+	noQueryRequest, _ := http.NewRequest("GET", "accounts", nil)
+
+	assert := assert.New(t)
+	assert.True(matcher.matches(closedRequest))
+	assert.False(matcher.matches(openRequest))
+	assert.False(matcher.matches(noQueryRequest))
+}
+
+func TestMatches_MatchesHeaderByRegex(t *testing.T) {
+	matcher, _ := newRequestMatcher(config.Endpoint{
+		RequestHeaders: []config.Matcher{
+			config.Matcher{Key: "accept", Regex: "application/xml"},
+		},
+	})
+	xmlRequest, _ := http.NewRequest("GET", "accounts", nil)
+	xmlRequest.Header.Set("Accept", "text/html, application/xml;q=0.9")
+	jsonRequest, _ := http.NewRequest("GET", "accounts", nil)
+	jsonRequest.Header.Set("Accept", "application/json")
+
+	assert := assert.New(t)
+	assert.True(matcher.matches(xmlRequest))
+	assert.False(matcher.matches(jsonRequest))
+}
+
+func TestMatches_MatchesPresenceAndAbsence(t *testing.T) {
+	matcher, _ := newRequestMatcher(config.Endpoint{
+		Query: []config.Matcher{
+			config.Matcher{Key: "page"},
+			config.Matcher{Key: "debug", Absent: true},
+		},
+	})
+	pagedRequest, _ := http.NewRequest("GET", "accounts?page=2", nil)
+	debugRequest, _ := http.NewRequest("GET", "accounts?page=2&debug=true", nil)
+	unpagedRequest, _ := http.NewRequest("GET", "accounts", nil)
+
+	assert := assert.New(t)
+	assert.True(matcher.matches(pagedRequest))
+	assert.False(matcher.matches(debugRequest))
+	assert.False(matcher.matches(unpagedRequest))
+}
+
+func TestHasSameConditions_ReturnsFalse_WhenConditionsDiffer(t *testing.T) {
+	noConditions, _ := newRequestMatcher(config.Endpoint{})
+	queryConditions, _ := newRequestMatcher(config.Endpoint{
+		Query: []config.Matcher{
+			config.Matcher{Key: "status", Value: "closed"},
+		},
+	})
+
+	assert := assert.New(t)
+	assert.False(noConditions.hasSameConditions(queryConditions))
+	assert.True(noConditions.hasSameConditions(noConditions))
+}
+
+func TestHasSameConditions_ReturnsTrue_WhenConditionsAreInAnotherOrder(t *testing.T) {
+	matcher, _ := newRequestMatcher(config.Endpoint{
+		Query: []config.Matcher{
+			config.Matcher{Key: "status", Value: "closed"},
+			config.Matcher{Key: "page", Regex: "^[0-9]+$"},
+		},
+	})
+	reordered, _ := newRequestMatcher(config.Endpoint{
+		Query: []config.Matcher{
+			config.Matcher{Key: "page", Regex: "^[0-9]+$"},
+			config.Matcher{Key: "status", Value: "closed"},
+		},
+	})
+
+	assert := assert.New(t)
+	assert.True(matcher.hasSameConditions(reordered))
+	assert.True(reordered.hasSameConditions(matcher))
+}
+
+const getInvoiceEnvelope = `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+	<soap:Body>
+		<b:GetInvoice xmlns:b="urn:billing">
+			<b:invoiceId>42</b:invoiceId>
+		</b:GetInvoice>
+	</soap:Body>
+</soap:Envelope>`
+
+func TestNewRequestMatcher_ReturnsError_WhenXPathInvalid(t *testing.T) {
+	endpoint := config.Endpoint{
+		XPath: []config.Matcher{
+			config.Matcher{Key: "//GetInvoice["},
+		},
+	}
+
+	result, err := newRequestMatcher(endpoint)
+
+	assert := assert.New(t)
+	assert.Error(err)
+	assert.Nil(result)
+}
+
+func TestMatches_MatchesSOAPAction_WhenSentAsHeaderOrContentType(t *testing.T) {
+	matcher, _ := newRequestMatcher(config.Endpoint{SOAPAction: "urn:billing/GetInvoice"})
+	soap11Request, _ := http.NewRequest("POST", "service", nil)
+	soap11Request.Header.Set("SOAPAction", `"urn:billing/GetInvoice"`)
+	soap12Request, _ := http.NewRequest("POST", "service", nil)
+	soap12Request.Header.Set("Content-Type", `application/soap+xml; charset=utf-8; action="urn:billing/GetInvoice"`)
+	otherRequest, _ := http.NewRequest("POST", "service", nil)
+	otherRequest.Header.Set("SOAPAction", `"urn:billing/PayInvoice"`)
+
+	assert := assert.New(t)
+	assert.True(matcher.matches(soap11Request))
+	assert.True(matcher.matches(soap12Request))
+	assert.False(matcher.matches(otherRequest))
+}
+
+func TestMatches_MatchesXPath_AndRestoresBody(t *testing.T) {
+	matcher, _ := newRequestMatcher(config.Endpoint{
+		XPath: []config.Matcher{
+			config.Matcher{Key: "//GetInvoice/invoiceId", Regex: "^4"},
+			config.Matcher{Key: "//Fault", Absent: true},
+		},
+	})
+	request, _ := http.NewRequest("POST", "service", strings.NewReader(getInvoiceEnvelope))
+
+	result := matcher.matches(request)
+	body, _ := ioutil.ReadAll(request.Body)
+
+	assert := assert.New(t)
+	assert.True(result)
+	assert.Equal(getInvoiceEnvelope, string(body))
+}
+
+func TestMatches_ReturnsFalse_WhenXPathNotSatisfied(t *testing.T) {
+	matcher, _ := newRequestMatcher(config.Endpoint{
+		XPath: []config.Matcher{
+			config.Matcher{Key: "//GetInvoice/invoiceId", Value: "7"},
+		},
+	})
+	otherRequest, _ := http.NewRequest("POST", "service", strings.NewReader(getInvoiceEnvelope))
+	notXMLRequest, _ := http.NewRequest("POST", "service", strings.NewReader(`{"invoiceId": 7}`))
+	noBodyRequest, _ := http.NewRequest("POST", "service", nil)
+
+	assert := assert.New(t)
+	assert.False(matcher.matches(otherRequest))
+	assert.False(matcher.matches(notXMLRequest))
+	assert.False(matcher.matches(noBodyRequest))
+}
+
+func TestSpecificity_CountsSOAPActionAndXPath(t *testing.T) {
+	matcher, _ := newRequestMatcher(config.Endpoint{
+		SOAPAction: "urn:billing/GetInvoice",
+		XPath: []config.Matcher{
+			config.Matcher{Key: "//invoiceId"},
+		},
+	})
+	soapActionOnly, _ := newRequestMatcher(config.Endpoint{SOAPAction: "urn:billing/GetInvoice"})
+
+	assert := assert.New(t)
+	assert.Equal(2, matcher.specificity())
+	assert.False(matcher.hasSameConditions(soapActionOnly))
+}
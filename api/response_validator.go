@@ -0,0 +1,181 @@
+package api
+
+import (
+	encodingJSON "encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/log"
+	"github.com/wcsanders1/MockApiHub/schema"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/sirupsen/logrus"
+)
+
+// responseValidator validates a response an endpoint serves against a JSON schema. A response served
+// from a file is validated again whenever the file changes; an inline response is validated once.
+type responseValidator struct {
+	schemaPath string
+	filePath   string
+	body       []byte
+	prefix     string
+	file       wrapper.IFileOps
+	log        *logrus.Entry
+	mutex      sync.Mutex
+	validated  bool
+	modTime    time.Time
+	size       int64
+	violations []string
+}
+
+func newResponseValidator(schemaPath, filePath string, file wrapper.IFileOps, logger *logrus.Entry) *responseValidator {
+	return &responseValidator{
+		schemaPath: schemaPath,
+		filePath:   filePath,
+		file:       file,
+		log: logger.WithFields(logrus.Fields{
+			log.SchemaField: schemaPath,
+			log.FileField:   filePath,
+		}),
+	}
+}
+
+func newInlineResponseValidator(schemaPath string, body []byte, file wrapper.IFileOps, logger *logrus.Entry) *responseValidator {
+	return &responseValidator{
+		schemaPath: schemaPath,
+		body:       body,
+		file:       file,
+		log:        logger.WithField(log.SchemaField, schemaPath),
+	}
+}
+
+// getResponseValidators returns a validator for each of the responses the endpoint serves: those
+// of its sequence, if it has one, and otherwise its file or inline body.
+func getResponseValidators(endpoint config.Endpoint, dir string, file wrapper.IFileOps, logger *logrus.Entry) []*responseValidator {
+	schemaPath := getFilePath(dir, endpoint.ResponseSchema)
+	if len(endpoint.Sequence) == 0 {
+		validator := getResponseValidator(schemaPath, dir, endpoint.File, endpoint.Body, endpoint.JSON, file, logger)
+		if validator == nil {
+			return nil
+		}
+		return []*responseValidator{validator}
+	}
+
+	var validators []*responseValidator
+	for i, response := range endpoint.Sequence {
+		validator := getResponseValidator(schemaPath, dir, response.File, response.Body, response.JSON, file, logger)
+		if validator == nil {
+			continue
+		}
+		validator.prefix = fmt.Sprintf("response %d: ", i+1)
+		validators = append(validators, validator)
+	}
+	return validators
+}
+
+func getResponseValidator(schemaPath, dir, filePath, body string, json interface{}, file wrapper.IFileOps, logger *logrus.Entry) *responseValidator {
+	switch {
+	case len(filePath) > 0:
+		return newResponseValidator(schemaPath, getFilePath(dir, filePath), file, logger)
+	case json != nil:
+		rendered, err := encodingJSON.Marshal(json)
+		if err != nil {
+			// The handler reports inline JSON that cannot be rendered when it is registered.
+			return nil
+		}
+		return newInlineResponseValidator(schemaPath, rendered, file, logger)
+	case len(body) > 0:
+		return newInlineResponseValidator(schemaPath, []byte(body), file, logger)
+	}
+	return nil
+}
+
+// validateIfChanged validates the response if it has not been validated since it last changed.
+func (v *responseValidator) validateIfChanged() {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if len(v.filePath) == 0 {
+		if !v.validated {
+			v.validated = true
+			v.violations = v.validateBody(v.body)
+		}
+		return
+	}
+
+	fileInfo, err := v.file.Stat(v.filePath)
+	if err != nil {
+		v.log.WithError(err).Error("error getting information on file to validate against response schema")
+		v.validated = false
+		v.violations = []string{err.Error()}
+		return
+	}
+
+	if v.validated && fileInfo.ModTime().Equal(v.modTime) && fileInfo.Size() == v.size {
+		return
+	}
+
+	v.validated = true
+	v.modTime = fileInfo.ModTime()
+	v.size = fileInfo.Size()
+	v.violations = v.validate()
+}
+
+func (v *responseValidator) validate() []string {
+	fileInfo, err := v.file.Open(v.filePath)
+	if err != nil {
+		v.log.WithError(err).Error("error opening file to validate against response schema")
+		return []string{err.Error()}
+	}
+
+	defer fileInfo.Close()
+
+	bytes, err := v.file.ReadAll(fileInfo)
+	if err != nil {
+		v.log.WithError(err).Error("error reading file to validate against response schema")
+		return []string{err.Error()}
+	}
+
+	return v.validateBody(bytes)
+}
+
+func (v *responseValidator) validateBody(body []byte) []string {
+	violations, err := schema.Validate(v.schemaPath, body, v.file)
+	if err != nil {
+		v.log.WithError(err).Error("error validating response against response schema")
+		return []string{err.Error()}
+	}
+
+	if len(violations) > 0 {
+		v.log.WithField(log.ViolationsField, violations).Warn("response does not match response schema")
+		return violations
+	}
+
+	v.log.Debug("response matches response schema")
+	return nil
+}
+
+func (v *responseValidator) getViolations() []string {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if len(v.prefix) == 0 {
+		return v.violations
+	}
+
+	violations := make([]string, 0, len(v.violations))
+	for _, violation := range v.violations {
+		violations = append(violations, v.prefix+violation)
+	}
+	return violations
+}
+
+func (v *responseValidator) wrap(next func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		v.validateIfChanged()
+		next(w, r)
+	}
+}
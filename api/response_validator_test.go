@@ -0,0 +1,145 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/fake"
+	"github.com/wcsanders1/MockApiHub/log"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func getFakeFixtureFileOps(modTime time.Time, fixture []byte) *wrapper.FakeFileOps {
+	fileInfo := new(fake.FileInfo)
+	fileInfo.On("ModTime").Return(modTime)
+	fileInfo.On("Size").Return(int64(len(fixture)))
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("Stat", "test/fixture").Return(fileInfo, nil)
+	fileOps.On("Open", "test/fixture").Return(os.NewFile(1, "fixture"), nil)
+	fileOps.On("Open", "test/schema").Return(os.NewFile(2, "schema"), nil)
+	fileOps.On("ReadAll", mock.MatchedBy(func(f *os.File) bool { return f.Name() == "fixture" })).Return(fixture, nil)
+	fileOps.On("ReadAll", mock.MatchedBy(func(f *os.File) bool { return f.Name() == "schema" })).Return(requiredNameSchema, nil)
+	return fileOps
+}
+
+func TestValidateIfChanged_RecordsViolations_WhenFileDoesNotMatchSchema(t *testing.T) {
+	fileOps := getFakeFixtureFileOps(time.Now(), []byte(`{"age": 36}`))
+	validator := newResponseValidator("test/schema", "test/fixture", fileOps, log.GetFakeLogger())
+
+	validator.validateIfChanged()
+
+	assert.Equal(t, 1, len(validator.getViolations()))
+}
+
+func TestValidateIfChanged_RecordsNoViolations_WhenFileMatchesSchema(t *testing.T) {
+	fileOps := getFakeFixtureFileOps(time.Now(), []byte(`{"name": "Ada"}`))
+	validator := newResponseValidator("test/schema", "test/fixture", fileOps, log.GetFakeLogger())
+
+	validator.validateIfChanged()
+
+	assert.Empty(t, validator.getViolations())
+}
+
+func TestValidateIfChanged_DoesNotRevalidate_WhenFileUnchanged(t *testing.T) {
+	fileOps := getFakeFixtureFileOps(time.Now(), []byte(`{"name": "Ada"}`))
+	validator := newResponseValidator("test/schema", "test/fixture", fileOps, log.GetFakeLogger())
+
+	validator.validateIfChanged()
+	validator.validateIfChanged()
+
+	fileOps.AssertNumberOfCalls(t, "Stat", 2)
+	fileOps.AssertNumberOfCalls(t, "Open", 2)
+}
+
+func TestValidateIfChanged_RecordsViolation_WhenStatFails(t *testing.T) {
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("Stat", "test/fixture").Return(new(fake.FileInfo), errors.New("no such file"))
+	validator := newResponseValidator("test/schema", "test/fixture", fileOps, log.GetFakeLogger())
+
+	validator.validateIfChanged()
+
+	assert.Equal(t, []string{"no such file"}, validator.getViolations())
+	fileOps.AssertNotCalled(t, "Open", mock.Anything)
+}
+
+func TestResponseValidatorWrap_CallsNext_WhenFileDoesNotMatchSchema(t *testing.T) {
+	fileOps := getFakeFixtureFileOps(time.Now(), []byte(`{"age": 36}`))
+	validator := newResponseValidator("test/schema", "test/fixture", fileOps, log.GetFakeLogger())
+	nextCalled := false
+	handler := validator.wrap(func(w http.ResponseWriter, r *http.Request) { nextCalled = true })
+	request, _ := http.NewRequest("GET", "test/url", nil)
+
+	handler(&fake.ResponseWriter{}, request)
+
+	assert.True(t, nextCalled)
+	assert.Equal(t, 1, len(validator.getViolations()))
+}
+
+func TestGetResponseSchemaViolations_ReturnsOnlyEndpointsWithViolations(t *testing.T) {
+	badValidator := newResponseValidator("test/schema", "test/fixture", getFakeFixtureFileOps(time.Now(), []byte(`{}`)), log.GetFakeLogger())
+	badValidator.validateIfChanged()
+	goodValidator := newResponseValidator("test/schema", "test/fixture", getFakeFixtureFileOps(time.Now(), []byte(`{"name": "Ada"}`)), log.GetFakeLogger())
+	goodValidator.validateIfChanged()
+	testAPI := API{
+		responseValidators: map[string][]*responseValidator{
+			"bad":  []*responseValidator{badValidator},
+			"good": []*responseValidator{goodValidator},
+		},
+	}
+
+	result := testAPI.GetResponseSchemaViolations()
+
+	assert := assert.New(t)
+	assert.Equal(1, len(result))
+	assert.Contains(result, "bad")
+}
+
+func getFakeSchemaFileOps() *wrapper.FakeFileOps {
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("Open", "test/schema").Return(os.NewFile(2, "schema"), nil)
+	fileOps.On("ReadAll", mock.MatchedBy(func(f *os.File) bool { return f.Name() == "schema" })).Return(requiredNameSchema, nil)
+	return fileOps
+}
+
+func TestGetResponseValidators_ValidatesInlineJSON(t *testing.T) {
+	endpoint := config.Endpoint{
+		ResponseSchema: "schema",
+		JSON:           map[string]interface{}{"age": 36},
+	}
+
+	validators := getResponseValidators(endpoint, "test", getFakeSchemaFileOps(), log.GetFakeLogger())
+	validators[0].validateIfChanged()
+
+	assert := assert.New(t)
+	assert.Equal(1, len(validators))
+	assert.Equal(1, len(validators[0].getViolations()))
+}
+
+func TestGetResponseValidators_ValidatesEachResponseOfSequence(t *testing.T) {
+	endpoint := config.Endpoint{
+		ResponseSchema: "schema",
+		Sequence: []config.Response{
+			config.Response{Body: `{"name": "Ada"}`},
+			config.Response{HTTPStatusCode: 204},
+			config.Response{Body: `{"age": 36}`},
+		},
+	}
+
+	validators := getResponseValidators(endpoint, "test", getFakeSchemaFileOps(), log.GetFakeLogger())
+	for _, validator := range validators {
+		validator.validateIfChanged()
+	}
+
+	assert := assert.New(t)
+	assert.Equal(2, len(validators))
+	assert.Empty(validators[0].getViolations())
+	assert.Equal(1, len(validators[1].getViolations()))
+	assert.Contains(validators[1].getViolations()[0], "response 3: ")
+}
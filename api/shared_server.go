@@ -0,0 +1,194 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/log"
+	"github.com/wcsanders1/MockApiHub/ref"
+	"github.com/wcsanders1/MockApiHub/str"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	// ISharedServer is an interface providing functionality to serve several mock APIs on one port.
+	ISharedServer interface {
+		Add(api *API) error
+		Start(defaultCert, defaultKey string) error
+		Shutdown() error
+		ServeHTTP(w http.ResponseWriter, r *http.Request)
+		GetPort() int
+	}
+
+	// SharedServer serves several mock APIs on one port, choosing the mock API for each request
+	// by the request's Host header and then by the mock APIs' routes.
+	SharedServer struct {
+		apis       []*API
+		server     wrapper.IServerOps
+		httpConfig config.HTTP
+		log        *logrus.Entry
+		creator    iCreator
+		mutex      sync.RWMutex
+	}
+)
+
+// NewSharedServer returns a new SharedServer listening according to the configuration provided.
+func NewSharedServer(httpConfig config.HTTP, logger *logrus.Entry) (*SharedServer, error) {
+	shared := &SharedServer{}
+	shared.log = logger.WithFields(logrus.Fields{
+		log.PortField:     httpConfig.Port,
+		log.UseTLSField:   httpConfig.UseTLS,
+		log.CertFileField: httpConfig.CertFile,
+		log.KeyFileField:  httpConfig.KeyFile,
+	})
+
+	if httpConfig.Port < 0 {
+		return nil, errors.New("invalid port provided")
+	}
+
+	shared.server = wrapper.NewServerOps(&http.Server{
+		Addr:    str.GetPort(int(httpConfig.Port)),
+		Handler: shared,
+	})
+	shared.httpConfig = httpConfig
+	shared.creator = newCreator(shared.log)
+	return shared, nil
+}
+
+// Add adds a mock API to the shared server. The mock API must be configured to use the same port
+// and TLS settings as the shared server.
+func (shared *SharedServer) Add(api *API) error {
+	if api.httpConfig.Port != shared.httpConfig.Port {
+		return errors.New("mock API is not configured to use the port of the shared server")
+	}
+
+	if api.httpConfig.UseTLS != shared.httpConfig.UseTLS ||
+		api.httpConfig.CertFile != shared.httpConfig.CertFile ||
+		api.httpConfig.KeyFile != shared.httpConfig.KeyFile {
+		return errors.New("mock API TLS configuration differs from that of the shared server")
+	}
+
+	shared.mutex.Lock()
+	defer shared.mutex.Unlock()
+
+	shared.apis = append(shared.apis, api)
+	sort.SliceStable(shared.apis, func(i, j int) bool {
+		return len(shared.apis[i].baseURL) > len(shared.apis[j].baseURL)
+	})
+	return nil
+}
+
+// Start starts the shared server, returning once it is listening. If the shared server was
+// configured to use a free port, the port chosen is assigned to the shared server and to each of its
+// mock APIs.
+func (shared *SharedServer) Start(defaultCert, defaultKey string) error {
+	contextLogger := shared.log.WithField(log.FuncField, ref.GetFuncName())
+	contextLogger.Debug("starting shared server")
+
+	port, err := shared.server.Listen()
+	if err != nil {
+		contextLogger.WithError(err).Error("error listening")
+		return err
+	}
+
+	if shared.httpConfig.Port == 0 {
+		shared.mutex.Lock()
+		shared.httpConfig.Port = config.Port(port)
+		for _, api := range shared.apis {
+			api.httpConfig.Port = config.Port(port)
+		}
+		shared.mutex.Unlock()
+		contextLogger.WithField(log.PortField, port).Info("listening on a free port")
+	}
+
+	return shared.creator.startAPI(defaultCert, defaultKey, shared.server, shared.httpConfig)
+}
+
+// Shutdown shuts down the shared server.
+func (shared *SharedServer) Shutdown() error {
+	contextLogger := shared.log.WithField(log.FuncField, ref.GetFuncName())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := shared.server.Shutdown(ctx); err != nil {
+		contextLogger.WithError(err).Error("error shutting down shared server")
+		return err
+	}
+
+	contextLogger.Info("successfully shut down shared server")
+	return nil
+}
+
+// GetPort returns the shared server's port number.
+func (shared *SharedServer) GetPort() int {
+	return int(shared.httpConfig.Port)
+}
+
+func (shared *SharedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	contextLogger := shared.log.WithFields(logrus.Fields{
+		log.FuncField:        ref.GetFuncName(),
+		log.PathField:        r.URL.Path,
+		log.VirtualHostField: r.Host,
+	})
+
+	if api := shared.getAPI(r); api != nil {
+		contextLogger.WithField(log.BaseURLField, api.baseURL).Debug("mock API found for request")
+		api.ServeHTTP(w, r)
+		return
+	}
+
+	contextLogger.Warn("no mock API found for request")
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte("mock API not found"))
+}
+
+// getAPI returns the mock API that should handle a request. Mock APIs whose virtual host matches
+// the request's Host header are preferred over mock APIs without a virtual host; among those, the
+// mock API with the longest base URL whose routes include the request's path is chosen.
+func (shared *SharedServer) getAPI(r *http.Request) *API {
+	shared.mutex.RLock()
+	defer shared.mutex.RUnlock()
+
+	host := getHostName(r.Host)
+	var hostAPIs, defaultAPIs []*API
+	for _, api := range shared.apis {
+		if len(api.virtualHost) == 0 {
+			defaultAPIs = append(defaultAPIs, api)
+		} else if strings.EqualFold(api.virtualHost, host) {
+			hostAPIs = append(hostAPIs, api)
+		}
+	}
+
+	candidates := hostAPIs
+	if len(candidates) == 0 {
+		candidates = defaultAPIs
+	}
+
+	for _, api := range candidates {
+		url := str.CleanURLWithOptions(r.URL.Path, api.caseSensitive, api.strictTrailingSlash)
+		if _, _, err := api.routeTree.GetRoute(url); err == nil {
+			return api
+		}
+	}
+
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	return nil
+}
+
+func getHostName(hostHeader string) string {
+	if host, _, err := net.SplitHostPort(hostHeader); err == nil {
+		return host
+	}
+	return hostHeader
+}
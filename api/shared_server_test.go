@@ -0,0 +1,140 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/fake"
+	"github.com/wcsanders1/MockApiHub/log"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func getStartedSharedAPI(t *testing.T, baseURL, virtualHost string) *API {
+	apiConfig := &config.APIConfig{
+		BaseURL:     baseURL,
+		VirtualHost: virtualHost,
+		HTTP: config.HTTP{
+			Port:   4000,
+			Shared: true,
+		},
+		Endpoints: map[string]config.Endpoint{
+			"getCustomers": config.Endpoint{
+				Path:   "customers",
+				Method: "GET",
+			},
+		},
+	}
+	api, err := NewAPI(apiConfig)
+	assert.NoError(t, err)
+	assert.NoError(t, api.Start("testDir", "", ""))
+	return api
+}
+
+func TestNewSharedServer_ReturnsError_WhenPortIsNegative(t *testing.T) {
+	result, err := NewSharedServer(config.HTTP{Port: -1}, log.GetFakeLogger())
+
+	assert := assert.New(t)
+	assert.Nil(result)
+	assert.Error(err)
+}
+
+func TestAdd_ReturnsError_WhenTLSConfigurationDiffers(t *testing.T) {
+	shared, _ := NewSharedServer(config.HTTP{Port: 4000, Shared: true}, log.GetFakeLogger())
+	api := getStartedSharedAPI(t, "customersApi", "")
+	api.httpConfig.UseTLS = true
+
+	err := shared.Add(api)
+
+	assert := assert.New(t)
+	assert.Error(err)
+	assert.Empty(shared.apis)
+}
+
+func TestAdd_ReturnsError_WhenPortDiffers(t *testing.T) {
+	shared, _ := NewSharedServer(config.HTTP{Port: 4001, Shared: true}, log.GetFakeLogger())
+
+	err := shared.Add(getStartedSharedAPI(t, "customersApi", ""))
+
+	assert.Error(t, err)
+}
+
+func TestGetAPI_ChoosesAPIByBaseURL_WhenNoVirtualHosts(t *testing.T) {
+	shared, _ := NewSharedServer(config.HTTP{Port: 4000, Shared: true}, log.GetFakeLogger())
+	customersAPI := getStartedSharedAPI(t, "customersApi", "")
+	studentsAPI := getStartedSharedAPI(t, "studentsApi/:districtNumber", "")
+	shared.Add(customersAPI)
+	shared.Add(studentsAPI)
+	customersRequest, _ := http.NewRequest("GET", "http://localhost:4000/customersApi/customers", nil)
+	studentsRequest, _ := http.NewRequest("GET", "http://localhost:4000/studentsApi/12/customers", nil)
+	unknownRequest, _ := http.NewRequest("GET", "http://localhost:4000/unknownApi/customers", nil)
+
+	assert := assert.New(t)
+	assert.Equal(customersAPI, shared.getAPI(customersRequest))
+	assert.Equal(studentsAPI, shared.getAPI(studentsRequest))
+	assert.Nil(shared.getAPI(unknownRequest))
+}
+
+func TestGetAPI_ChoosesAPIByVirtualHost_WhenHostMatches(t *testing.T) {
+	shared, _ := NewSharedServer(config.HTTP{Port: 4000, Shared: true}, log.GetFakeLogger())
+	billingAPI := getStartedSharedAPI(t, "", "billing.local")
+	ordersAPI := getStartedSharedAPI(t, "", "orders.local")
+	defaultAPI := getStartedSharedAPI(t, "", "")
+	shared.Add(billingAPI)
+	shared.Add(ordersAPI)
+	shared.Add(defaultAPI)
+	billingRequest, _ := http.NewRequest("GET", "http://Billing.local:4000/customers", nil)
+	ordersRequest, _ := http.NewRequest("GET", "http://orders.local:4000/customers", nil)
+	otherRequest, _ := http.NewRequest("GET", "http://localhost:4000/customers", nil)
+
+	assert := assert.New(t)
+	assert.Equal(billingAPI, shared.getAPI(billingRequest))
+	assert.Equal(ordersAPI, shared.getAPI(ordersRequest))
+	assert.Equal(defaultAPI, shared.getAPI(otherRequest))
+}
+
+func TestSharedServerServeHTTP_WritesStatusNotFound_WhenNoAPIFound(t *testing.T) {
+	shared, _ := NewSharedServer(config.HTTP{Port: 4000, Shared: true}, log.GetFakeLogger())
+	writer := fake.ResponseWriter{}
+	writer.On("WriteHeader", mock.AnythingOfType("int")).Return(1)
+	writer.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
+	request, _ := http.NewRequest("GET", "http://localhost:4000/customers", nil)
+
+	shared.ServeHTTP(&writer, request)
+
+	writer.AssertCalled(t, "WriteHeader", http.StatusNotFound)
+}
+
+func TestStart_DoesNotStartServer_WhenAPIIsShared(t *testing.T) {
+	creator := fakeAPICreator{}
+	testAPI := API{
+		httpConfig: config.HTTP{Port: 4000, Shared: true},
+		endpoints:  map[string]config.Endpoint{},
+		log:        log.GetFakeLogger(),
+		creator:    &creator,
+		handlers:   make(map[string]map[string]func(http.ResponseWriter, *http.Request)),
+	}
+
+	err := testAPI.Start("testDir", "testCert", "testKey")
+
+	assert.NoError(t, err)
+	creator.AssertNotCalled(t, "startAPI", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSharedServerShutdown_ReturnsError_WhenShutdownFails(t *testing.T) {
+	fakeServer := wrapper.FakeServerOps{}
+	fakeServer.On("Shutdown", mock.Anything).Return(errors.New(""))
+	shared := SharedServer{
+		log:    log.GetFakeLogger(),
+		server: &fakeServer,
+	}
+
+	err := shared.Shutdown()
+
+	assert.Error(t, err)
+	fakeServer.AssertCalled(t, "Shutdown", mock.Anything)
+}
@@ -0,0 +1,312 @@
+package api
+
+import (
+	"bytes"
+	encodingJSON "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/json"
+	"github.com/wcsanders1/MockApiHub/log"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EndpointTypeSSE is the type of endpoints that stream server-sent events.
+const EndpointTypeSSE = "sse"
+
+// IsEndpointType returns whether endpointType is a valid type of endpoint. Endpoints without a type
+// serve one response to each request.
+func IsEndpointType(endpointType string) bool {
+	switch strings.ToLower(endpointType) {
+	case "", EndpointTypeSSE, EndpointTypeWebSocket, EndpointTypeGraphQL:
+		return true
+	}
+	return false
+}
+
+// sseTemplateData is what the data of an event rendered from a template can refer to.
+type sseTemplateData struct {
+	Number int
+	Time   string
+}
+
+// getSSEHandler returns a handler that streams the endpoint's server-sent events, resuming after
+// the event named by a Last-Event-ID header.
+func (c creator) getSSEHandler(endpoint config.Endpoint, dir string, file wrapper.IFileOps) func(w http.ResponseWriter, r *http.Request) {
+	path := getFilePath(dir, endpoint.File)
+	contextLogger := c.log.WithFields(logrus.Fields{
+		log.FuncField: "server-sent events handler for mock API",
+		log.PathField: path,
+	})
+
+	if endpoint.ExpandEnv {
+		file = &envFileOps{file}
+	}
+
+	keepAlive, err := parseDuration(endpoint.SSE.KeepAlive)
+	if err != nil {
+		return getErrorHandler(fmt.Errorf("invalid keep-alive interval: %v", err), contextLogger)
+	}
+
+	var dataTemplate *template.Template
+	if len(path) == 0 {
+		if dataTemplate, err = getSSETemplate(endpoint.SSE); err != nil {
+			return getErrorHandler(err, contextLogger)
+		}
+	}
+
+	headers := append([]config.Header{
+		{Key: "Content-Type", Value: "text/event-stream"},
+		{Key: "Cache-Control", Value: "no-cache"},
+	}, endpoint.Headers...)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, header := range headers {
+			w.Header().Set(header.Key, header.Value)
+		}
+
+		if endpoint.AllowCORS {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeError(errors.New("streaming is not supported"), w)
+			return
+		}
+
+		if code := endpoint.HTTPStatusCode; code > 0 && code != http.StatusOK && len(http.StatusText(code)) > 0 {
+			w.WriteHeader(code)
+			return
+		}
+
+		var getEvent func(i int) (config.SSEEvent, bool, error)
+		if dataTemplate != nil {
+			getEvent = getTemplateEvents(dataTemplate, endpoint.SSE, r.Header.Get("Last-Event-ID"))
+		} else {
+			events, err := getFileEvents(path, file, r.Header.Get("Last-Event-ID"))
+			if err != nil {
+				contextLogger.WithError(err).Error("error reading server-sent events")
+				writeError(err, w)
+				return
+			}
+			getEvent = func(i int) (config.SSEEvent, bool, error) {
+				if i >= len(events) {
+					return config.SSEEvent{}, false, nil
+				}
+				return events[i], true, nil
+			}
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if endpoint.SSE.Retry > 0 {
+			fmt.Fprintf(w, "retry: %d\n\n", endpoint.SSE.Retry)
+		}
+		flusher.Flush()
+
+		var keepAliveTicks <-chan time.Time
+		if keepAlive > 0 {
+			ticker := time.NewTicker(keepAlive)
+			defer ticker.Stop()
+			keepAliveTicks = ticker.C
+		}
+
+		// wait waits for the delay, or until the client disconnects if the delay is negative, sending
+		// keep-alive comments meanwhile. It returns false if the client disconnects.
+		wait := func(delay time.Duration) bool {
+			var elapsed <-chan time.Time
+			if delay >= 0 {
+				timer := time.NewTimer(delay)
+				defer timer.Stop()
+				elapsed = timer.C
+			}
+			for {
+				select {
+				case <-r.Context().Done():
+					return false
+				case <-elapsed:
+					return true
+				case <-keepAliveTicks:
+					io.WriteString(w, ": keep-alive\n\n")
+					flusher.Flush()
+				}
+			}
+		}
+
+		for i := 0; ; i++ {
+			event, exists, err := getEvent(i)
+			if err != nil {
+				contextLogger.WithError(err).Error("error rendering server-sent event")
+				return
+			}
+			if !exists {
+				break
+			}
+
+			delay, err := parseDuration(event.Delay)
+			if err != nil {
+				contextLogger.WithError(err).Warn("invalid server-sent event delay -- sending the event without delay")
+			}
+			if !wait(delay) {
+				contextLogger.Debug("client disconnected from server-sent events")
+				return
+			}
+
+			writeSSEEvent(w, event)
+			flusher.Flush()
+		}
+
+		if endpoint.SSE.HoldOpen {
+			wait(-1)
+		}
+	}
+}
+
+// FindSSEProblems returns descriptions of the problems with the server-sent events settings of an
+// endpoint, other than those with its file.
+func FindSSEProblems(endpoint config.Endpoint) []string {
+	var problems []string
+	if _, err := parseDuration(endpoint.SSE.KeepAlive); err != nil {
+		problems = append(problems, fmt.Sprintf("invalid keep-alive interval: %v", err))
+	}
+	if len(endpoint.File) == 0 {
+		if _, err := getSSETemplate(endpoint.SSE); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	return problems
+}
+
+func getSSETemplate(sse config.SSE) (*template.Template, error) {
+	data := getTextData(sse.Template.Data)
+	if len(data) == 0 && len(sse.Template.Event) == 0 {
+		return nil, errors.New("server-sent events endpoint has neither a file nor a template")
+	}
+
+	delay, err := parseDuration(sse.Template.Delay)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server-sent event delay: %v", err)
+	}
+	if delay == 0 && sse.Count == 0 {
+		return nil, errors.New("server-sent events template needs a delay or a count")
+	}
+
+	return template.New("data").Parse(data)
+}
+
+// getTemplateEvents returns a function that renders the template's events, numbered from 1 in their
+// ids, skipping those up to the number given by lastEventID.
+func getTemplateEvents(dataTemplate *template.Template, sse config.SSE, lastEventID string) func(i int) (config.SSEEvent, bool, error) {
+	start, err := strconv.Atoi(lastEventID)
+	if err != nil || start < 0 {
+		start = 0
+	}
+
+	return func(i int) (config.SSEEvent, bool, error) {
+		number := start + i + 1
+		if sse.Count > 0 && number > sse.Count {
+			return config.SSEEvent{}, false, nil
+		}
+
+		var data bytes.Buffer
+		templateData := sseTemplateData{
+			Number: number,
+			Time:   time.Now().Format(time.RFC3339),
+		}
+		if err := dataTemplate.Execute(&data, templateData); err != nil {
+			return config.SSEEvent{}, false, err
+		}
+
+		return config.SSEEvent{
+			Event: sse.Template.Event,
+			ID:    strconv.Itoa(number),
+			Data:  data.String(),
+			Delay: sse.Template.Delay,
+		}, true, nil
+	}
+}
+
+// getFileEvents returns the events in a file, skipping those up to the event whose id is
+// lastEventID, if there is one.
+func getFileEvents(path string, file wrapper.IFileOps, lastEventID string) ([]config.SSEEvent, error) {
+	contents, err := json.GetJSON(path, file)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []config.SSEEvent
+	if err := encodingJSON.Unmarshal(contents, &events); err != nil {
+		return nil, fmt.Errorf("server-sent events file must be a JSON array of events: %v", err)
+	}
+
+	if len(lastEventID) > 0 {
+		for i, event := range events {
+			if event.ID == lastEventID {
+				return events[i+1:], nil
+			}
+		}
+	}
+	return events, nil
+}
+
+func writeSSEEvent(w io.Writer, event config.SSEEvent) {
+	var message strings.Builder
+	if len(event.Event) > 0 {
+		fmt.Fprintf(&message, "event: %s\n", event.Event)
+	}
+	if len(event.ID) > 0 {
+		fmt.Fprintf(&message, "id: %s\n", event.ID)
+	}
+	for _, line := range strings.Split(getTextData(event.Data), "\n") {
+		fmt.Fprintf(&message, "data: %s\n", line)
+	}
+	message.WriteString("\n")
+	io.WriteString(w, message.String())
+}
+
+// getTextData returns the data of an event or frame as it is sent: strings as they are and other
+// values as JSON.
+func getTextData(data interface{}) string {
+	switch value := data.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		contents, err := encodingJSON.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(contents)
+	}
+}
+
+func parseDuration(duration string) (time.Duration, error) {
+	if len(duration) == 0 {
+		return 0, nil
+	}
+	parsed, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0, err
+	}
+	if parsed < 0 {
+		return 0, fmt.Errorf("duration %s is negative", duration)
+	}
+	return parsed, nil
+}
+
+func getErrorHandler(err error, logger *logrus.Entry) func(w http.ResponseWriter, r *http.Request) {
+	logger.WithError(err).Error("error creating handler")
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeError(err, w)
+	}
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/log"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var sseEvents = []byte(`[
+	{"event": "created", "id": "1", "data": {"id": 7}},
+	{"event": "updated", "id": "2", "data": "first line\nsecond line"},
+	{"id": "3", "data": "done", "delay": "1ms"}
+]`)
+
+func getSSEResponse(t *testing.T, endpoint config.Endpoint, lastEventID string) *httptest.ResponseRecorder {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	dir := writeTempFile(t, "events.json", sseEvents)
+	defer os.RemoveAll(dir)
+	endpoint.Type = EndpointTypeSSE
+	w := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "test/url", nil)
+	if len(lastEventID) > 0 {
+		request.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	creator.getHandler(endpoint, dir, &wrapper.FileOps{})(w, request)
+	return w
+}
+
+func TestGetHandler_StreamsFileEvents_WhenEndpointTypeSSE(t *testing.T) {
+	w := getSSEResponse(t, config.Endpoint{File: "events.json", SSE: config.SSE{Retry: 3000}}, "")
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Equal("text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal("no-cache", w.Header().Get("Cache-Control"))
+	assert.True(w.Flushed)
+	assert.Equal("retry: 3000\n\n"+
+		"event: created\nid: 1\ndata: {\"id\":7}\n\n"+
+		"event: updated\nid: 2\ndata: first line\ndata: second line\n\n"+
+		"id: 3\ndata: done\n\n", w.Body.String())
+}
+
+func TestGetHandler_ResumesFileEvents_WhenLastEventIDSent(t *testing.T) {
+	w := getSSEResponse(t, config.Endpoint{File: "events.json"}, "2")
+
+	assert.Equal(t, "id: 3\ndata: done\n\n", w.Body.String())
+}
+
+func TestGetHandler_StreamsAllFileEvents_WhenLastEventIDUnknown(t *testing.T) {
+	w := getSSEResponse(t, config.Endpoint{File: "events.json"}, "42")
+
+	assert.Equal(t, 3, strings.Count(w.Body.String(), "id: "))
+}
+
+func TestGetHandler_RendersTemplateEvents_WhenNoFileProvided(t *testing.T) {
+	endpoint := config.Endpoint{
+		SSE: config.SSE{
+			Template: config.SSEEvent{Event: "tick", Data: map[string]interface{}{"count": "{{.Number}}"}},
+			Count:    3,
+		},
+	}
+
+	fromStart := getSSEResponse(t, endpoint, "")
+	resumed := getSSEResponse(t, endpoint, "2")
+
+	assert := assert.New(t)
+	assert.Equal("event: tick\nid: 1\ndata: {\"count\":\"1\"}\n\n"+
+		"event: tick\nid: 2\ndata: {\"count\":\"2\"}\n\n"+
+		"event: tick\nid: 3\ndata: {\"count\":\"3\"}\n\n", fromStart.Body.String())
+	assert.Equal("event: tick\nid: 3\ndata: {\"count\":\"3\"}\n\n", resumed.Body.String())
+}
+
+func TestGetHandler_WritesKeepAlive_WhenWaitingForEvent(t *testing.T) {
+	endpoint := config.Endpoint{
+		SSE: config.SSE{
+			Template:  config.SSEEvent{Data: "ping", Delay: "60ms"},
+			Count:     1,
+			KeepAlive: "10ms",
+		},
+	}
+
+	w := getSSEResponse(t, endpoint, "")
+
+	assert := assert.New(t)
+	assert.Contains(w.Body.String(), ": keep-alive\n\n")
+	assert.True(strings.HasSuffix(w.Body.String(), "id: 1\ndata: ping\n\n"))
+}
+
+func TestGetHandler_StopsStreaming_WhenClientDisconnects(t *testing.T) {
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	endpoint := config.Endpoint{
+		Type: EndpointTypeSSE,
+		SSE:  config.SSE{Template: config.SSEEvent{Data: "ping", Delay: "1ms"}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	request, _ := http.NewRequest("GET", "test/url", nil)
+	w := httptest.NewRecorder()
+	done := make(chan bool)
+
+	go func() {
+		creator.getHandler(endpoint, "testDir", &wrapper.FakeFileOps{})(w, request.WithContext(ctx))
+		done <- true
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handler did not return after the client disconnected")
+	}
+}
+
+func TestGetHandler_WritesError_WhenSSETemplateHasNoDelayOrCount(t *testing.T) {
+	w := getSSEResponse(t, config.Endpoint{SSE: config.SSE{Template: config.SSEEvent{Data: "ping"}}}, "")
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestGetHandler_WritesError_WhenSSETemplateDelayNegative(t *testing.T) {
+	w := getSSEResponse(t, config.Endpoint{SSE: config.SSE{Count: 1, Template: config.SSEEvent{Data: "ping", Delay: "-1s"}}}, "")
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestFindSSEProblems_ReportsNegativeDurations(t *testing.T) {
+	problems := FindSSEProblems(config.Endpoint{SSE: config.SSE{
+		KeepAlive: "-5s",
+		Count:     1,
+		Template:  config.SSEEvent{Data: "ping", Delay: "-1s"},
+	}})
+
+	assert := assert.New(t)
+	assert.Equal(2, len(problems))
+	assert.Contains(problems[0], "duration -5s is negative")
+	assert.Contains(problems[1], "duration -1s is negative")
+}
+
+func TestGetHandler_WritesStatusCode_WhenSSEStatusCodeNotOK(t *testing.T) {
+	w := getSSEResponse(t, config.Endpoint{File: "events.json", HTTPStatusCode: http.StatusServiceUnavailable}, "")
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusServiceUnavailable, w.Code)
+	assert.Empty(w.Body.String())
+}
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/json"
+	"github.com/wcsanders1/MockApiHub/log"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+// EndpointTypeWebSocket is the type of endpoints that hold scripted WebSocket conversations.
+const EndpointTypeWebSocket = "websocket"
+
+// closeTimeout is how long a client has to acknowledge that the mock API closed the connection.
+const closeTimeout = time.Second
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+type (
+	frameRule struct {
+		config.FrameRule
+		regex *regexp.Regexp
+	}
+
+	// webSocketConversation sends the frames of a connection, which can be written from several
+	// scripts at once, until the connection closes.
+	webSocketConversation struct {
+		conn   *websocket.Conn
+		log    *logrus.Entry
+		mutex  sync.Mutex
+		closed bool
+		done   chan struct{}
+	}
+)
+
+// FindWebSocketProblems returns descriptions of the problems with the WebSocket settings of an
+// endpoint.
+func FindWebSocketProblems(endpoint config.Endpoint) []string {
+	var problems []string
+	if !strings.EqualFold(endpoint.Method, http.MethodGet) {
+		problems = append(problems, "WebSocket endpoints must use the GET method")
+	}
+	problems = append(problems, getFrameProblems("on connect", endpoint.WebSocket.OnConnect)...)
+
+	for i, rule := range endpoint.WebSocket.Rules {
+		if _, err := compileFrameRule(rule); err != nil {
+			problems = append(problems, fmt.Sprintf("rule %d: %v", i+1, err))
+		}
+		problems = append(problems, getFrameProblems(fmt.Sprintf("rule %d reply", i+1), rule.Reply)...)
+	}
+	return problems
+}
+
+func getFrameProblems(name string, frames []config.Frame) []string {
+	var problems []string
+	for i, frame := range frames {
+		if _, err := parseDuration(frame.Delay); err != nil {
+			problems = append(problems, fmt.Sprintf("%s frame %d: invalid delay: %v", name, i+1, err))
+		}
+		if frame.Close != 0 && (frame.Close < websocket.CloseNormalClosure || frame.Close > 4999) {
+			problems = append(problems, fmt.Sprintf("%s frame %d: invalid close code: %d", name, i+1, frame.Close))
+		}
+	}
+	return problems
+}
+
+// getWebSocketHandler returns a handler that upgrades requests to WebSocket connections and holds the
+// endpoint's conversation on them. Every frame a client sends is logged and recorded in the
+// endpoint's frame journal, if the request carries one.
+func (c creator) getWebSocketHandler(endpoint config.Endpoint) func(w http.ResponseWriter, r *http.Request) {
+	contextLogger := c.log.WithFields(logrus.Fields{
+		log.FuncField: "WebSocket handler for mock API",
+		log.PathField: endpoint.Path,
+	})
+
+	rules := make([]frameRule, len(endpoint.WebSocket.Rules))
+	for i, rule := range endpoint.WebSocket.Rules {
+		compiled, err := compileFrameRule(rule)
+		if err != nil {
+			return getErrorHandler(fmt.Errorf("rule %d: %v", i+1, err), contextLogger)
+		}
+		rules[i] = compiled
+	}
+
+	header := http.Header{}
+	for _, h := range endpoint.Headers {
+		header.Set(h.Key, h.Value)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, header)
+		if err != nil {
+			contextLogger.WithError(err).Error("error upgrading request to WebSocket connection")
+			return
+		}
+
+		conversation := &webSocketConversation{
+			conn: conn,
+			log:  contextLogger,
+			done: make(chan struct{}),
+		}
+		defer conversation.end()
+
+		journal := getFrameJournal(r)
+		go conversation.send(endpoint.WebSocket.OnConnect)
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				contextLogger.WithError(err).Debug("WebSocket connection closed")
+				return
+			}
+
+			contextLogger.WithField(log.FrameField, string(data)).Info("received WebSocket frame")
+			if journal != nil {
+				journal.record(data)
+			}
+			if rule := matchFrameRule(rules, data); rule != nil {
+				go conversation.send(rule.Reply)
+			}
+		}
+	}
+}
+
+func compileFrameRule(rule config.FrameRule) (frameRule, error) {
+	compiled := frameRule{FrameRule: rule}
+	if len(rule.JSONPath) > 0 {
+		if err := json.ValidatePath(rule.JSONPath); err != nil {
+			return compiled, err
+		}
+	}
+	if len(rule.Regex) > 0 {
+		regex, err := regexp.Compile(rule.Regex)
+		if err != nil {
+			return compiled, fmt.Errorf("invalid regex: %v", err)
+		}
+		compiled.regex = regex
+	}
+	return compiled, nil
+}
+
+func matchFrameRule(rules []frameRule, data []byte) *frameRule {
+	for i := range rules {
+		if rules[i].matches(data) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+func (rule frameRule) matches(data []byte) bool {
+	text := string(data)
+	if len(rule.JSONPath) > 0 {
+		value, exists, err := json.Lookup(data, rule.JSONPath)
+		if err != nil || !exists {
+			return false
+		}
+		text = getTextData(value)
+	}
+
+	if len(rule.Value) > 0 && text != rule.Value {
+		return false
+	}
+	return rule.regex == nil || rule.regex.MatchString(text)
+}
+
+// send sends the frames in turn, stopping if the connection closes.
+func (c *webSocketConversation) send(frames []config.Frame) {
+	for _, frame := range frames {
+		delay, err := parseDuration(frame.Delay)
+		if err != nil {
+			c.log.WithError(err).Warn("invalid WebSocket frame delay -- sending the frame without delay")
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-c.done:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := c.write(frame); err != nil {
+			c.log.WithError(err).Debug("stopped sending WebSocket frames")
+			return
+		}
+	}
+}
+
+func (c *webSocketConversation) write(frame config.Frame) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.closed {
+		return errors.New("connection is closing")
+	}
+
+	if frame.Close != 0 {
+		c.closed = true
+		message := websocket.FormatCloseMessage(frame.Close, frame.Reason)
+		if err := c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(closeTimeout)); err != nil {
+			return err
+		}
+		// The connection closes once the client acknowledges, or once it has had time to.
+		return c.conn.SetReadDeadline(time.Now().Add(closeTimeout))
+	}
+
+	return c.conn.WriteMessage(websocket.TextMessage, []byte(getTextData(frame.Data)))
+}
+
+func (c *webSocketConversation) end() {
+	close(c.done)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.closed = true
+	c.conn.Close()
+}
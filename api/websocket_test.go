@@ -0,0 +1,191 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/log"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var tradingFeed = config.WebSocket{
+	OnConnect: []config.Frame{
+		{Data: map[string]interface{}{"type": "welcome"}},
+	},
+	Rules: []config.FrameRule{
+		{Value: "ping", Reply: []config.Frame{{Data: "pong"}}},
+		{JSONPath: "$.action", Value: "subscribe", Reply: []config.Frame{
+			{Data: "subscribed"},
+			{Data: `{"symbol": "ACME", "price": 10}`, Delay: "10ms"},
+		}},
+		{Regex: "^bye", Reply: []config.Frame{{Close: 4001, Reason: "session ended"}}},
+	},
+}
+
+func dialWebSocket(t *testing.T, endpoint config.Endpoint, logger *logrus.Entry) (*websocket.Conn, func()) {
+	creator := creator{
+		log: logger,
+	}
+	endpoint.Type = EndpointTypeWebSocket
+	endpoint.Method = http.MethodGet
+	server := httptest.NewServer(http.HandlerFunc(creator.getHandler(endpoint, "testDir", nil)))
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	return conn, func() {
+		conn.Close()
+		server.Close()
+	}
+}
+
+func readText(t *testing.T, conn *websocket.Conn) string {
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestWebSocketHandler_SendsOnConnectFrames_WhenClientConnects(t *testing.T) {
+	conn, closeConn := dialWebSocket(t, config.Endpoint{WebSocket: tradingFeed}, log.GetFakeLogger())
+	defer closeConn()
+
+	assert.Equal(t, `{"type":"welcome"}`, readText(t, conn))
+}
+
+func TestWebSocketHandler_RepliesWithRuleFrames_WhenFrameMatchesRule(t *testing.T) {
+	conn, closeConn := dialWebSocket(t, config.Endpoint{WebSocket: tradingFeed}, log.GetFakeLogger())
+	defer closeConn()
+	readText(t, conn)
+
+	conn.WriteMessage(websocket.TextMessage, []byte("ping"))
+	pong := readText(t, conn)
+	conn.WriteMessage(websocket.TextMessage, []byte(`{"action": "subscribe", "symbols": ["ACME"]}`))
+	subscribed := readText(t, conn)
+	quote := readText(t, conn)
+
+	assert := assert.New(t)
+	assert.Equal("pong", pong)
+	assert.Equal("subscribed", subscribed)
+	assert.Equal(`{"symbol": "ACME", "price": 10}`, quote)
+}
+
+func TestWebSocketHandler_IgnoresFrame_WhenNoRuleMatches(t *testing.T) {
+	conn, closeConn := dialWebSocket(t, config.Endpoint{WebSocket: tradingFeed}, log.GetFakeLogger())
+	defer closeConn()
+	readText(t, conn)
+
+	conn.WriteMessage(websocket.TextMessage, []byte(`{"action": "unsubscribe"}`))
+	conn.WriteMessage(websocket.TextMessage, []byte("ping"))
+
+	assert.Equal(t, "pong", readText(t, conn))
+}
+
+func TestWebSocketHandler_ClosesWithCode_WhenReplyIsCloseFrame(t *testing.T) {
+	conn, closeConn := dialWebSocket(t, config.Endpoint{WebSocket: tradingFeed}, log.GetFakeLogger())
+	defer closeConn()
+	readText(t, conn)
+
+	conn.WriteMessage(websocket.TextMessage, []byte("bye now"))
+	_, _, err := conn.ReadMessage()
+
+	assert := assert.New(t)
+	closeErr, ok := err.(*websocket.CloseError)
+	assert.True(ok)
+	assert.Equal(4001, closeErr.Code)
+	assert.Equal("session ended", closeErr.Text)
+}
+
+func TestWebSocketHandler_LogsReceivedFrames_WhenClientSendsFrames(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	conn, closeConn := dialWebSocket(t, config.Endpoint{WebSocket: tradingFeed}, logrus.NewEntry(logger))
+	defer closeConn()
+	readText(t, conn)
+
+	conn.WriteMessage(websocket.TextMessage, []byte("ping"))
+	readText(t, conn)
+
+	var frames []interface{}
+	for _, entry := range hook.AllEntries() {
+		if frame, exists := entry.Data[log.FrameField]; exists {
+			frames = append(frames, frame)
+		}
+	}
+	assert.Equal(t, []interface{}{"ping"}, frames)
+}
+
+func TestWebSocketHandler_RecordsReceivedFrames_WhenRequestCarriesJournal(t *testing.T) {
+	journal := &frameJournal{}
+	creator := creator{
+		log: log.GetFakeLogger(),
+	}
+	endpoint := config.Endpoint{Type: EndpointTypeWebSocket, Method: http.MethodGet, WebSocket: tradingFeed}
+	server := httptest.NewServer(http.HandlerFunc(journal.wrap(creator.getHandler(endpoint, "testDir", nil))))
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	readText(t, conn)
+
+	conn.WriteMessage(websocket.TextMessage, []byte("ping"))
+	readText(t, conn)
+	conn.WriteMessage(websocket.TextMessage, []byte(`{"action": "unknown"}`))
+	conn.WriteMessage(websocket.TextMessage, []byte("ping"))
+	readText(t, conn)
+
+	frames := journal.getFrames()
+	assert := assert.New(t)
+	assert.Equal(3, len(frames))
+	assert.Equal("ping", frames[0].Data)
+	assert.Equal(`{"action": "unknown"}`, frames[1].Data)
+	assert.False(frames[0].Time.IsZero())
+}
+
+func TestFrameJournal_DropsOldestFrame_WhenFull(t *testing.T) {
+	journal := &frameJournal{}
+	for i := 0; i <= maxJournalFrames; i++ {
+		journal.record([]byte(strconv.Itoa(i)))
+	}
+
+	frames := journal.getFrames()
+	assert := assert.New(t)
+	assert.Equal(maxJournalFrames, len(frames))
+	assert.Equal("1", frames[0].Data)
+	assert.Equal(strconv.Itoa(maxJournalFrames), frames[maxJournalFrames-1].Data)
+}
+
+func TestFindWebSocketProblems_ReturnsProblems_WhenSettingsInvalid(t *testing.T) {
+	endpoint := config.Endpoint{
+		Method: "POST",
+		WebSocket: config.WebSocket{
+			OnConnect: []config.Frame{{Delay: "soon"}},
+			Rules: []config.FrameRule{
+				{Regex: "(", JSONPath: "$.action"},
+				{JSONPath: "action", Reply: []config.Frame{{Close: 999}}},
+			},
+		},
+	}
+
+	result := FindWebSocketProblems(endpoint)
+
+	assert := assert.New(t)
+	assert.Equal(5, len(result))
+	assert.Contains(result, "WebSocket endpoints must use the GET method")
+	assert.Contains(result, "rule 2 reply frame 1: invalid close code: 999")
+	assert.Empty(FindWebSocketProblems(config.Endpoint{Method: "GET", WebSocket: tradingFeed}))
+}
@@ -2,10 +2,13 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/wcsanders1/MockApiHub/constants"
 	"github.com/wcsanders1/MockApiHub/wrapper"
@@ -14,16 +17,27 @@ import (
 type (
 	// AppConfig is application configuration.
 	AppConfig struct {
-		HTTP HTTP
-		Log  Log
+		HTTP        HTTP
+		Log         Log
+		PortsFile   string
+		APIDirs     []string
+		APILogLevel string
+		Defaults    APIConfig
 	}
 
 	// APIConfig is configuration for an individual mock API.
 	APIConfig struct {
-		HTTP      HTTP
-		BaseURL   string
-		Endpoints map[string]Endpoint
-		Log       Log
+		HTTP                HTTP
+		BaseURL             string
+		Endpoints           map[string]Endpoint
+		Log                 Log
+		CaseSensitive       bool
+		StrictTrailingSlash bool
+		VirtualHost         string
+		Include             []string
+		EndpointDefaults    Endpoint
+		Protocol            string
+		DescriptorSet       string
 	}
 
 	// Log is configuration for logging.
@@ -40,41 +54,208 @@ type (
 
 	// HTTP contains information regarding server setup.
 	HTTP struct {
-		Port     int
+		Port     Port
 		UseTLS   bool
 		CertFile string
 		KeyFile  string
+		Shared   bool
 	}
 
-	// Endpoint contains information regarding an endpoint.
+	// Endpoint contains information regarding an endpoint. The response body is the contents of
+	// File if it is set; otherwise it is JSON, rendered as JSON, if it is set; otherwise it is Body.
 	Endpoint struct {
-		Path             string
-		File             string
-		Method           string
-		Headers          []Header
-		EnforceValidJSON bool
-		AllowCORS        bool
-		HTTPStatusCode   int
+		Path                    string
+		File                    string
+		Method                  string
+		Headers                 []Header
+		EnforceValidJSON        bool
+		AllowCORS               bool
+		HTTPStatusCode          int
+		Query                   []Matcher
+		RequestHeaders          []Matcher
+		RequestSchema           string
+		RequestSchemaStatusCode int
+		ResponseSchema          string
+		Body                    string
+		JSON                    interface{}
+		Sequence                []Response
+		ExpandEnv               bool
+		ContentType             string
+		CacheFile               bool
+		DisableCacheHeaders     bool
+		Compression             string
+		Type                    string
+		SSE                     SSE
+		WebSocket               WebSocket
+		GraphQL                 GraphQL
+		GRPC                    GRPC
+		EnforceValidXML         bool
+		SOAPAction              string
+		XPath                   []Matcher
 	}
 
+	// Response is one of the responses an endpoint returns in turn. The first request to the
+	// endpoint receives the first response, the second request the second response, and so on;
+	// once the responses run out, the last is repeated. Headers are added to those of the endpoint,
+	// and a HTTPStatusCode of 0 means that of the endpoint is used.
+	Response struct {
+		File           string
+		Body           string
+		JSON           interface{}
+		HTTPStatusCode int
+		Headers        []Header
+	}
+
+	// SSE configures an endpoint of type sse, which streams server-sent events. The events are read
+	// from the endpoint's File, a JSON array of events, or, if it has none, rendered from Template.
+	// A Count of 0 renders events until the client disconnects.
+	SSE struct {
+		Template  SSEEvent
+		Count     int
+		KeepAlive string
+		Retry     int
+		HoldOpen  bool
+	}
+
+	// SSEEvent is a server-sent event, which is sent Delay after the previous event. Data that is not
+	// a string is sent as JSON.
+	SSEEvent struct {
+		Event string
+		ID    string
+		Data  interface{}
+		Delay string
+	}
+
+	// WebSocket configures an endpoint of type websocket. The OnConnect frames are sent when a client
+	// connects, and a frame the client sends is answered with the Reply of the first rule it matches.
+	WebSocket struct {
+		OnConnect []Frame
+		Rules     []FrameRule
+	}
+
+	// Frame is a WebSocket frame, which is sent Delay after the previous frame. Data that is not a
+	// string is sent as JSON. A frame with a Close code closes the connection with that code and
+	// Reason instead.
+	Frame struct {
+		Data   interface{}
+		Delay  string
+		Close  int
+		Reason string
+	}
+
+	// FrameRule matches the frames a client sends. The text of a frame, or the value at JSONPath in
+	// it, must equal Value if it is set and match Regex if it is set. A rule with neither matches every
+	// frame, or every frame with a value at JSONPath.
+	FrameRule struct {
+		Value    string
+		Regex    string
+		JSONPath string
+		Reply    []Frame
+	}
+
+	// GraphQL configures an endpoint of type graphql, which resolves GraphQL requests against the
+	// Schema file, written in the schema definition language, from the Fixtures file, if it has one.
+	GraphQL struct {
+		Schema   string
+		Fixtures string
+	}
+
+	// GRPC configures an endpoint of a mock API whose protocol is grpc. The endpoint's Path names the
+	// method it answers, such as orders.OrderService/GetOrder, and its Headers are sent as response
+	// metadata. Status is the name of the status code the method returns, such as NOT_FOUND, with
+	// Message; Delay is the time before each message of a server-streaming method.
+	GRPC struct {
+		Status   string
+		Message  string
+		Trailers []Header
+		Delay    string
+	}
+
+	// Port is a port number. In a configuration file it can also be given as "auto", which,
+	// like 0, means that a free port is chosen when the server starts.
+	Port int
+
 	// Header contains the keys and values to put on response headers.
 	Header struct {
 		Key   string
 		Value string
 	}
 
+	// Matcher contains a condition that a request's query string, headers or XML body must satisfy
+	// for an endpoint to handle the request. For the body, the key is an XPath expression, which is
+	// present if it has a value. If Absent is true, the key must not be present. Otherwise, if Value
+	// is provided, the key must have that exact value; if Regex is provided, the key must have a
+	// value matching it; and if neither is provided, the key must be present.
+	Matcher struct {
+		Key    string
+		Value  string
+		Regex  string
+		Absent bool
+	}
+
 	// IManager provides functionality to manage configurations, such as getting
 	// a mock API configuration from the disk.
 	IManager interface {
-		GetAPIConfig(file os.FileInfo) (*APIConfig, error)
+		GetAPIConfig(apiDir string, file os.FileInfo) (*APIConfig, error)
 	}
 
 	// Manager is a concrete implementation of IManager.
 	Manager struct {
-		file wrapper.IFileOps
+		file     wrapper.IFileOps
+		defaults *APIConfig
 	}
 )
 
+// ErrNotAPIDir is returned when a mock API configuration is requested for a file or directory that
+// is not a mock API directory.
+var ErrNotAPIDir = errors.New("not a mock API directory")
+
+// AutoPort is the configuration value requesting that a free port be chosen when a server starts.
+const AutoPort = "auto"
+
+// UnmarshalTOML decodes a port number, or AutoPort, from TOML. A port given as a string can refer to
+// environment variables, as described by ExpandEnv.
+func (port *Port) UnmarshalTOML(value interface{}) error {
+	switch v := value.(type) {
+	case int64:
+		*port = Port(v)
+		return nil
+	case string:
+		parsed, err := ParsePort(ExpandEnv(v))
+		if err != nil {
+			return fmt.Errorf("invalid port: %v", value)
+		}
+		*port = parsed
+		return nil
+	}
+	return fmt.Errorf("invalid port: %v", value)
+}
+
+// UnmarshalJSON decodes a port number, or AutoPort, from JSON.
+func (port *Port) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if number, ok := value.(float64); ok && number == float64(int64(number)) {
+		value = int64(number)
+	}
+	return port.UnmarshalTOML(value)
+}
+
+// ParsePort parses a port number, or AutoPort, from a string.
+func ParsePort(value string) (Port, error) {
+	if strings.EqualFold(value, AutoPort) {
+		return 0, nil
+	}
+
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 0 {
+		return 0, fmt.Errorf("invalid port: %s", value)
+	}
+	return Port(port), nil
+}
+
 // NewConfigManager returns a reference to a new Manager.
 func NewConfigManager() *Manager {
 	return &Manager{
@@ -82,47 +263,78 @@ func NewConfigManager() *Manager {
 	}
 }
 
-// GetAPIConfig gets a mock API configuration from the disk.
-func (mgr *Manager) GetAPIConfig(fileInfo os.FileInfo) (*APIConfig, error) {
+// NewConfigManagerWithDefaults returns a reference to a new Manager whose mock API configurations
+// inherit the defaults provided.
+func NewConfigManagerWithDefaults(defaults *APIConfig) *Manager {
+	return &Manager{
+		file:     &wrapper.FileOps{},
+		defaults: defaults,
+	}
+}
+
+// GetAppConfig gets the application configuration from the file provided.
+func (mgr *Manager) GetAppConfig(path string) (*AppConfig, error) {
+	var appConfig AppConfig
+	if _, err := DecodeFile(mgr.file, path, &appConfig); err != nil {
+		return nil, err
+	}
+	return &appConfig, nil
+}
+
+// GetAPIConfig gets a mock API configuration from the disk, given the directory containing the
+// mock API directory and the mock API directory's file information. It returns ErrNotAPIDir if the
+// file information is not that of a mock API directory.
+func (mgr *Manager) GetAPIConfig(apiDir string, fileInfo os.FileInfo) (*APIConfig, error) {
 	dir := fileInfo.Name()
 	if !fileInfo.IsDir() || !isAPI(dir) {
-		return nil, errors.New("not a mock API directory")
+		return nil, ErrNotAPIDir
 	}
 
-	apiConfig, err := mgr.getAPIConfigFromDir(dir)
+	apiConfig, err := mgr.getAPIConfigFromDir(apiDir, dir)
 	if err != nil {
 		return nil, err
 	}
+	if apiConfig == nil {
+		return nil, fmt.Errorf("no mock API configuration file in %s/%s", apiDir, dir)
+	}
 	return apiConfig, nil
 }
 
-func (mgr *Manager) getAPIConfigFromDir(dir string) (*APIConfig, error) {
-	files, _ := mgr.file.ReadDir(fmt.Sprintf("%s/%s", constants.APIDir, dir))
-	for _, file := range files {
-		if isAPIConfig(file.Name()) {
-			apiConfig, err := mgr.decodeAPIConfig(dir, file.Name())
-			if err != nil {
-				return nil, err
-			}
-			return apiConfig, nil
-		}
+func (mgr *Manager) getAPIConfigFromDir(apiDir, dir string) (*APIConfig, error) {
+	path, err := FindAPIConfigFile(mgr.file, fmt.Sprintf("%s/%s", apiDir, dir))
+	if err != nil || len(path) == 0 {
+		return nil, err
 	}
-	return nil, nil
+
+	return mgr.decodeAPIConfig(apiDir, dir, filepath.Base(path))
 }
 
-func (mgr *Manager) decodeAPIConfig(dir string, fileName string) (*APIConfig, error) {
-	path := fmt.Sprintf("%s/%s/%s", constants.APIDir, dir, fileName)
-	var Manager APIConfig
-	if _, err := mgr.file.DecodeFile(path, &Manager); err != nil {
-		return nil, err
-	}
-	return &Manager, nil
+func (mgr *Manager) decodeAPIConfig(apiDir, dir, fileName string) (*APIConfig, error) {
+	path := fmt.Sprintf("%s/%s/%s", apiDir, dir, fileName)
+	return DecodeAPIConfigFile(mgr.file, path, mgr.defaults)
 }
 
+// isAPIConfig reports whether a file in a mock API directory is its configuration. Since mock APIs
+// also serve YAML and JSON files, a YAML or JSON configuration file must be named like a mock API
+// directory, e.g., customersApi.json.
 func isAPIConfig(fileName string) bool {
-	return filepath.Ext(fileName) == ".toml"
+	ext := getExt(fileName)
+	if ext == tomlExt {
+		return true
+	}
+	return isYAMLOrJSON(fileName) && isAPI(fileName[:len(fileName)-len(ext)])
 }
 
 func isAPI(dir string) bool {
 	return len(dir) > 3 && dir[len(dir)-3:] == constants.APIDirExt
 }
+
+// IsAPIDir reports whether the directory name provided is that of a mock API directory.
+func IsAPIDir(dir string) bool {
+	return isAPI(dir)
+}
+
+// IsAPIConfigFile reports whether the file name provided is that of a mock API configuration file.
+func IsAPIConfigFile(fileName string) bool {
+	return isAPIConfig(fileName)
+}
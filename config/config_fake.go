@@ -12,7 +12,7 @@ type FakeManager struct {
 }
 
 // GetAPIConfig is a fake implementation of GetAPIConfig().
-func (mgr *FakeManager) GetAPIConfig(file os.FileInfo) (*APIConfig, error) {
-	args := mgr.Called(file)
+func (mgr *FakeManager) GetAPIConfig(apiDir string, file os.FileInfo) (*APIConfig, error) {
+	args := mgr.Called(apiDir, file)
 	return args.Get(0).(*APIConfig), args.Error(1)
 }
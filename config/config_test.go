@@ -59,7 +59,7 @@ func TestDecodeAPIConfig_ReturnsAPIConfig_WhenDecodeSuccessful(t *testing.T) {
 		file: fileOps,
 	}
 
-	result, err := mgr.decodeAPIConfig(dir, file)
+	result, err := mgr.decodeAPIConfig(constants.APIDir, dir, file)
 
 	assert := assert.New(t)
 	assert.Nil(err)
@@ -79,7 +79,7 @@ func TestDecodeAPIConfig_ReturnsError_WhenDecodeFails(t *testing.T) {
 		file: fileOps,
 	}
 
-	result, err := mgr.decodeAPIConfig(dir, file)
+	result, err := mgr.decodeAPIConfig(constants.APIDir, dir, file)
 
 	assert := assert.New(t)
 	assert.Error(err)
@@ -98,7 +98,7 @@ func TestGetAPIConfig_ReturnsAPIConfig_WhenProvidedAPI(t *testing.T) {
 		file: fileOps,
 	}
 
-	result, err := mgr.GetAPIConfig(fileInfo)
+	result, err := mgr.GetAPIConfig(constants.APIDir, fileInfo)
 
 	expectedDir := fmt.Sprintf("%s/%s", constants.APIDir, dir)
 	assert := assert.New(t)
@@ -114,7 +114,7 @@ func TestGetAPIConfig_ReturnsError_WhenNotProvidedAPI(t *testing.T) {
 		file: fileOps,
 	}
 
-	result, err := mgr.GetAPIConfig(fileInfo)
+	result, err := mgr.GetAPIConfig(constants.APIDir, fileInfo)
 
 	assert := assert.New(t)
 	assert.Error(err)
@@ -133,7 +133,7 @@ func TestGetAPIConfig_ReturnsError_WhenDecodeFails(t *testing.T) {
 		file: fileOps,
 	}
 
-	result, err := mgr.GetAPIConfig(fileInfo)
+	result, err := mgr.GetAPIConfig(constants.APIDir, fileInfo)
 
 	expectedDir := fmt.Sprintf("%s/%s", constants.APIDir, dir)
 	assert := assert.New(t)
@@ -152,7 +152,7 @@ func TestGetAPIConfigFromDir_ReturnsAPIConfig_WhenProvidedAPI(t *testing.T) {
 		file: fileOps,
 	}
 
-	result, err := mgr.getAPIConfigFromDir(dir)
+	result, err := mgr.getAPIConfigFromDir(constants.APIDir, dir)
 
 	expectedDir := fmt.Sprintf("%s/%s", constants.APIDir, dir)
 	assert := assert.New(t)
@@ -172,7 +172,7 @@ func TestGetAPIConfigFromDir_ReturnsNil_WhenNoAPIsInDirectory(t *testing.T) {
 		file: fileOps,
 	}
 
-	result, err := mgr.getAPIConfigFromDir(dir)
+	result, err := mgr.getAPIConfigFromDir(constants.APIDir, dir)
 
 	expectedDir := fmt.Sprintf("%s/%s", constants.APIDir, dir)
 	assert := assert.New(t)
@@ -193,3 +193,66 @@ func getFakeFileInfoAndCollection(dir, file string) (os.FileInfo, []os.FileInfo)
 
 	return fileInfo, fileInfoCollection
 }
+
+func TestPortUnmarshalTOML_DecodesPort_WhenGivenNumberOrAuto(t *testing.T) {
+	var numbered, auto HTTP
+
+	_, numberedErr := toml.Decode("port = 5001", &numbered)
+	_, autoErr := toml.Decode(`port = "auto"`, &auto)
+
+	assert := assert.New(t)
+	assert.NoError(numberedErr)
+	assert.NoError(autoErr)
+	assert.Equal(Port(5001), numbered.Port)
+	assert.Equal(Port(0), auto.Port)
+}
+
+func TestPortUnmarshalTOML_ReturnsError_WhenGivenOtherString(t *testing.T) {
+	var httpConfig HTTP
+
+	_, err := toml.Decode(`port = "free"`, &httpConfig)
+
+	assert.Error(t, err)
+}
+
+func TestGetAppConfig_ReturnsAppConfig_WhenDecodeSuccessful(t *testing.T) {
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("DecodeFile", mock.AnythingOfType("string"), mock.Anything).Return(toml.MetaData{}, nil)
+	mgr := Manager{
+		file: fileOps,
+	}
+
+	result, err := mgr.GetAppConfig("ci/app_config.toml")
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.NotNil(result)
+	fileOps.AssertCalled(t, "DecodeFile", "ci/app_config.toml", mock.AnythingOfType("*config.AppConfig"))
+}
+
+func TestGetAppConfig_ReturnsError_WhenDecodeFails(t *testing.T) {
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("DecodeFile", mock.AnythingOfType("string"), mock.Anything).Return(toml.MetaData{}, errors.New(""))
+	mgr := Manager{
+		file: fileOps,
+	}
+
+	result, err := mgr.GetAppConfig("app_config.toml")
+
+	assert := assert.New(t)
+	assert.Error(err)
+	assert.Nil(result)
+}
+
+func TestParsePort_ReturnsPort_WhenGivenNumberOrAuto(t *testing.T) {
+	numbered, numberedErr := ParsePort("5001")
+	auto, autoErr := ParsePort("AUTO")
+	_, invalidErr := ParsePort("five")
+
+	assert := assert.New(t)
+	assert.NoError(numberedErr)
+	assert.NoError(autoErr)
+	assert.Error(invalidErr)
+	assert.Equal(Port(5001), numbered)
+	assert.Equal(Port(0), auto)
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	tomlExt = ".toml"
+	yamlExt = ".yaml"
+	ymlExt  = ".yml"
+	jsonExt = ".json"
+)
+
+// DecodeFile decodes a configuration file in TOML, YAML or JSON format, chosen by the file's
+// extension, into v. Keys are matched to fields ignoring case, whatever the format. Files with any
+// other extension are decoded as TOML. The metadata returned describes the keys of TOML files only.
+// Environment variables in string values are expanded, as described by ExpandEnv.
+func DecodeFile(file wrapper.IFileOps, path string, v interface{}) (toml.MetaData, error) {
+	return decodeFile(file, path, v, false)
+}
+
+// DecodeFileStrictly decodes a configuration file as DecodeFile does, but also returns an error if
+// a YAML or JSON file has a key that matches no field. Such keys in TOML files are found through the
+// metadata returned.
+func DecodeFileStrictly(file wrapper.IFileOps, path string, v interface{}) (toml.MetaData, error) {
+	return decodeFile(file, path, v, true)
+}
+
+// FindAPIConfigFile returns the path of the configuration file in a mock API directory, or an empty
+// string if there is none. It returns an error if the directory cannot be read or has more than one
+// configuration file.
+func FindAPIConfigFile(file wrapper.IFileOps, dir string) (string, error) {
+	files, err := file.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	var configFiles []string
+	for _, f := range files {
+		if isAPIConfig(f.Name()) {
+			configFiles = append(configFiles, f.Name())
+		}
+	}
+
+	switch len(configFiles) {
+	case 0:
+		return "", nil
+	case 1:
+		return fmt.Sprintf("%s/%s", dir, configFiles[0]), nil
+	default:
+		return "", fmt.Errorf("more than one mock API configuration file in %s: %s", dir, strings.Join(configFiles, ", "))
+	}
+}
+
+func decodeFile(file wrapper.IFileOps, path string, v interface{}, strict bool) (toml.MetaData, error) {
+	var md toml.MetaData
+	var err error
+	if isYAMLOrJSON(path) {
+		err = decodeYAMLOrJSON(file, path, v, strict)
+	} else {
+		md, err = file.DecodeFile(path, v)
+	}
+	if err != nil {
+		return md, err
+	}
+
+	expandEnvInValues(v, os.LookupEnv)
+	return md, nil
+}
+
+func decodeYAMLOrJSON(file wrapper.IFileOps, path string, v interface{}, strict bool) error {
+	f, err := file.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	contents, err := file.ReadAll(f)
+	if err != nil {
+		return err
+	}
+
+	if getExt(path) != jsonExt {
+		if contents, err = yamlToJSON(contents); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
+
+func yamlToJSON(contents []byte) ([]byte, error) {
+	var value interface{}
+	if err := yaml.Unmarshal(contents, &value); err != nil {
+		return nil, err
+	}
+	if value == nil {
+		value = map[string]interface{}{}
+	}
+	return json.Marshal(convertYAMLValue(value))
+}
+
+func convertYAMLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[fmt.Sprint(key)] = convertYAMLValue(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = convertYAMLValue(item)
+		}
+		return result
+	}
+	return value
+}
+
+func isYAMLOrJSON(path string) bool {
+	switch getExt(path) {
+	case yamlExt, ymlExt, jsonExt:
+		return true
+	}
+	return false
+}
+
+func getExt(path string) string {
+	return strings.ToLower(filepath.Ext(path))
+}
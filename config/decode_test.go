@@ -0,0 +1,120 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeFile_DecodesAPIConfig_WhenFileIsYAML(t *testing.T) {
+	var apiConfig APIConfig
+
+	_, err := DecodeFile(&wrapper.FileOps{}, "testdata/yamlApi/ordersApi.yaml", &apiConfig)
+
+	endpoint := apiConfig.Endpoints["getOrders"]
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal("ordersApi", apiConfig.BaseURL)
+	assert.Equal(Port(0), apiConfig.HTTP.Port)
+	assert.Equal("orders.json", endpoint.File)
+	assert.Equal(200, endpoint.HTTPStatusCode)
+	assert.Equal([]Header{{Key: "Content-Type", Value: "application/json"}}, endpoint.Headers)
+	assert.Equal([]Matcher{{Key: "page", Regex: "[0-9]+"}}, endpoint.Query)
+}
+
+func TestDecodeFile_DecodesAPIConfig_WhenFileIsJSON(t *testing.T) {
+	var apiConfig APIConfig
+
+	_, err := DecodeFile(&wrapper.FileOps{}, "testdata/jsonApi/ordersApi.json", &apiConfig)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal("ordersApi", apiConfig.BaseURL)
+	assert.Equal(Port(5005), apiConfig.HTTP.Port)
+	assert.True(apiConfig.Endpoints["getOrders"].EnforceValidJSON)
+}
+
+func TestDecodeFileStrictly_ReturnsError_WhenYAMLHasUnknownKey(t *testing.T) {
+	var apiConfig APIConfig
+
+	_, err := DecodeFileStrictly(&wrapper.FileOps{}, "testdata/duplicateApi/ordersApi.yml", &apiConfig)
+
+	assert := assert.New(t)
+	assert.Error(err)
+	assert.Contains(err.Error(), "prot")
+}
+
+func TestFindAPIConfigFile_ReturnsPath_WhenDirHasOneConfigFile(t *testing.T) {
+	result, err := FindAPIConfigFile(&wrapper.FileOps{}, "testdata/jsonApi")
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal("testdata/jsonApi/ordersApi.json", result)
+}
+
+func TestFindAPIConfigFile_ReturnsError_WhenDirHasSeveralConfigFiles(t *testing.T) {
+	result, err := FindAPIConfigFile(&wrapper.FileOps{}, "testdata/duplicateApi")
+
+	assert := assert.New(t)
+	assert.Empty(result)
+	assert.EqualError(err, "more than one mock API configuration file in testdata/duplicateApi: ordersApi.toml, ordersApi.yml")
+}
+
+func TestFindAPIConfigFile_ReturnsError_WhenDirCannotBeRead(t *testing.T) {
+	result, err := FindAPIConfigFile(&wrapper.FileOps{}, "testdata/missingApi")
+
+	assert := assert.New(t)
+	assert.Empty(result)
+	assert.Error(err)
+}
+
+func TestIsAPIConfig_ReturnsTrue_WhenYAMLOrJSONNamedLikeAPI(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(isAPIConfig("ordersApi.yaml"))
+	assert.True(isAPIConfig("ordersApi.yml"))
+	assert.True(isAPIConfig("ordersApi.JSON"))
+	assert.False(isAPIConfig("orders.json"))
+	assert.False(isAPIConfig("orders.yaml"))
+}
+
+func TestPortUnmarshalJSON_DecodesPort_WhenGivenNumberOrAuto(t *testing.T) {
+	var numbered, auto HTTP
+
+	numberedErr := json.Unmarshal([]byte(`{"port": 5001}`), &numbered)
+	autoErr := json.Unmarshal([]byte(`{"port": "AUTO"}`), &auto)
+	fractionErr := json.Unmarshal([]byte(`{"port": 50.5}`), &HTTP{})
+
+	assert := assert.New(t)
+	assert.Nil(numberedErr)
+	assert.Nil(autoErr)
+	assert.Error(fractionErr)
+	assert.Equal(Port(5001), numbered.Port)
+	assert.Equal(Port(0), auto.Port)
+}
+
+func TestDecodeFile_DecodesInlineBodies_WhenEndpointHasBodyAndJSON(t *testing.T) {
+	var apiConfig APIConfig
+	contents := `[endpoints.getStatus]
+path = "status"
+method = "GET"
+json = { status = "ok", checks = [1, 2] }
+
+[endpoints.getText]
+path = "text"
+method = "GET"
+body = '''
+line one
+line two'''
+`
+
+	_, err := toml.Decode(contents, &apiConfig)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal(map[string]interface{}{"status": "ok", "checks": []interface{}{int64(1), int64(2)}}, apiConfig.Endpoints["getStatus"].JSON)
+	assert.Equal("line one\nline two", apiConfig.Endpoints["getText"].Body)
+}
@@ -0,0 +1,205 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/wcsanders1/MockApiHub/wrapper"
+)
+
+const (
+	endpointsKey        = "endpoints"
+	endpointDefaultsKey = "endpointDefaults"
+	headersKey          = "headers"
+)
+
+// DecodeAPIConfigFile decodes a mock API configuration file, in any format DecodeFile supports, on top
+// of the defaults provided and of the files it includes. Included files are decoded in order, each on
+// top of the last, and included paths are relative to the including file. Keys set in the file
+// override those set in included files, which override the defaults, except that lists of headers
+// are combined. Endpoint defaults are then applied to every endpoint in the same way.
+func DecodeAPIConfigFile(file wrapper.IFileOps, path string, defaults *APIConfig) (*APIConfig, error) {
+	var apiConfig APIConfig
+	if _, err := DecodeFile(file, path, &apiConfig); err != nil {
+		return nil, err
+	}
+
+	if defaults != nil && reflect.DeepEqual(*defaults, APIConfig{}) {
+		defaults = nil
+	}
+	if defaults == nil && len(apiConfig.Include) == 0 && reflect.DeepEqual(apiConfig.EndpointDefaults, Endpoint{}) {
+		return &apiConfig, nil
+	}
+
+	merged := make(map[string]interface{})
+	if defaults != nil {
+		defaultsMap, err := normalizeMap(defaults)
+		if err != nil {
+			return nil, err
+		}
+		merged = defaultsMap
+	}
+
+	layer, err := decodeLayer(file, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	merged = mergeMaps(merged, layer)
+	applyEndpointDefaults(merged)
+
+	contents, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+
+	apiConfig = APIConfig{}
+	if err := json.Unmarshal(contents, &apiConfig); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &apiConfig, nil
+}
+
+// decodeLayer decodes a configuration file into a map on top of the files it includes.
+func decodeLayer(file wrapper.IFileOps, path string, including []string) (map[string]interface{}, error) {
+	for _, includingPath := range including {
+		if filepath.Clean(includingPath) == filepath.Clean(path) {
+			return nil, fmt.Errorf("include cycle: %s -> %s", strings.Join(including, " -> "), path)
+		}
+	}
+
+	layer, err := decodeToMap(file, path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each file's values are expanded as the file is decoded, so that the defaults, which were
+	// expanded with the app configuration, are not expanded a second time once merged.
+	includeKey, include := getKey(layer, "include")
+	if len(includeKey) > 0 {
+		delete(layer, includeKey)
+	}
+	expandEnvInValues(&layer, os.LookupEnv)
+	if len(includeKey) == 0 {
+		return layer, nil
+	}
+
+	includes, ok := include.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: include must be a list of files", path)
+	}
+
+	merged := make(map[string]interface{})
+	for _, item := range includes {
+		includePath, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: include must be a list of files", path)
+		}
+		includePath = ExpandEnv(includePath)
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(filepath.Dir(path), includePath)
+		}
+
+		included, err := decodeLayer(file, includePath, append(including, path))
+		if err != nil {
+			return nil, err
+		}
+		merged = mergeMaps(merged, included)
+	}
+	return mergeMaps(merged, layer), nil
+}
+
+func decodeToMap(file wrapper.IFileOps, path string) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	if !isYAMLOrJSON(path) {
+		if _, err := file.DecodeFile(path, &result); err != nil {
+			return nil, err
+		}
+		// Values decoded from TOML, such as arrays of tables, are normalized through JSON.
+		return normalizeMap(result)
+	}
+
+	if err := decodeYAMLOrJSON(file, path, &result, false); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func normalizeMap(v interface{}) (map[string]interface{}, error) {
+	contents, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{})
+	if err := json.Unmarshal(contents, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// mergeMaps returns base with the keys of override set on top of it. Keys are compared ignoring
+// case, nested maps are merged, lists of headers are combined and other values are replaced.
+func mergeMaps(base, override map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(base)+len(override))
+	for key, value := range base {
+		result[key] = value
+	}
+
+	for key, value := range override {
+		existingKey, existing := getKey(result, key)
+		if len(existingKey) > 0 {
+			delete(result, existingKey)
+		}
+
+		existingMap, existingIsMap := existing.(map[string]interface{})
+		valueMap, valueIsMap := value.(map[string]interface{})
+		existingList, existingIsList := existing.([]interface{})
+		valueList, valueIsList := value.([]interface{})
+		switch {
+		case existingIsMap && valueIsMap:
+			result[key] = mergeMaps(existingMap, valueMap)
+		case existingIsList && valueIsList && strings.EqualFold(key, headersKey):
+			result[key] = append(append([]interface{}{}, existingList...), valueList...)
+		default:
+			result[key] = value
+		}
+	}
+	return result
+}
+
+func applyEndpointDefaults(apiConfig map[string]interface{}) {
+	defaultsKey, defaults := getKey(apiConfig, endpointDefaultsKey)
+	defaultsMap, ok := defaults.(map[string]interface{})
+	if !ok {
+		return
+	}
+	delete(apiConfig, defaultsKey)
+
+	_, endpoints := getKey(apiConfig, endpointsKey)
+	endpointsMap, ok := endpoints.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for name, endpoint := range endpointsMap {
+		if endpointMap, ok := endpoint.(map[string]interface{}); ok {
+			endpointsMap[name] = mergeMaps(defaultsMap, endpointMap)
+		}
+	}
+}
+
+func getKey(m map[string]interface{}, key string) (string, interface{}) {
+	if value, exists := m[key]; exists {
+		return key, value
+	}
+	for existingKey, value := range m {
+		if strings.EqualFold(existingKey, key) {
+			return existingKey, value
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeAPIConfigFile_MergesIncludes_WhenFileIncludesOthers(t *testing.T) {
+	result, err := DecodeAPIConfigFile(&wrapper.FileOps{}, "testdata/includeApi/ordersApi.toml", nil)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal("ordersApi", result.BaseURL)
+	assert.Equal(Port(5005), result.HTTP.Port)
+	assert.False(result.HTTP.UseTLS)
+	assert.Equal("certs/shared.crt", result.HTTP.CertFile)
+	assert.True(result.Log.LoggingEnabled)
+	assert.Equal("warn", result.Log.Level)
+
+	getOrders := result.Endpoints["getOrders"]
+	assert.True(getOrders.AllowCORS)
+	assert.Equal([]Header{{Key: "Content-Type", Value: "application/json"}}, getOrders.Headers)
+
+	getOrderNotes := result.Endpoints["getOrderNotes"]
+	assert.False(getOrderNotes.AllowCORS)
+	assert.Equal("notes.txt", getOrderNotes.File)
+	assert.Equal([]Header{{Key: "Content-Type", Value: "application/json"}, {Key: "Content-Type", Value: "text/plain"}}, getOrderNotes.Headers)
+}
+
+func TestDecodeAPIConfigFile_AppliesDefaults_WhenFileDoesNotOverride(t *testing.T) {
+	defaults := &APIConfig{
+		Log:              Log{MaxFileDaysAge: 3, Level: "error"},
+		CaseSensitive:    true,
+		EndpointDefaults: Endpoint{Headers: []Header{{Key: "X-Mock", Value: "true"}}},
+	}
+
+	result, err := DecodeAPIConfigFile(&wrapper.FileOps{}, "testdata/includeApi/ordersApi.toml", defaults)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.True(result.CaseSensitive)
+	assert.Equal(3, result.Log.MaxFileDaysAge)
+	assert.Equal("warn", result.Log.Level)
+	assert.Equal([]Header{{Key: "X-Mock", Value: "true"}, {Key: "Content-Type", Value: "application/json"}}, result.Endpoints["getOrders"].Headers)
+}
+
+func TestDecodeAPIConfigFile_DoesNotExpandDefaultsAgain_WhenMerging(t *testing.T) {
+	defaults := &APIConfig{
+		EndpointDefaults: Endpoint{Headers: []Header{{Key: "X-Template", Value: "${ORDER_ID}"}}},
+	}
+
+	result, err := DecodeAPIConfigFile(&wrapper.FileOps{}, "testdata/includeApi/ordersApi.toml", defaults)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal(Header{Key: "X-Template", Value: "${ORDER_ID}"}, result.Endpoints["getOrders"].Headers[0])
+}
+
+func TestDecodeAPIConfigFile_ReturnsError_WhenIncludesFormCycle(t *testing.T) {
+	result, err := DecodeAPIConfigFile(&wrapper.FileOps{}, "testdata/cycleApi/a.toml", nil)
+
+	assert := assert.New(t)
+	assert.Nil(result)
+	assert.Error(err)
+	assert.Contains(err.Error(), "include cycle")
+}
+
+func TestDecodeAPIConfigFile_DecodesFileAlone_WhenNoIncludesOrDefaults(t *testing.T) {
+	result, err := DecodeAPIConfigFile(&wrapper.FileOps{}, "testdata/jsonApi/ordersApi.json", &APIConfig{})
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal(Port(5005), result.HTTP.Port)
+	assert.Nil(result.Endpoints["getOrders"].Headers)
+}
+
+func TestMergeMaps_IgnoresKeyCase_WhenMerging(t *testing.T) {
+	base := map[string]interface{}{"BaseURL": "a", "HTTP": map[string]interface{}{"Port": 1, "UseTLS": true}}
+	override := map[string]interface{}{"baseUrl": "b", "http": map[string]interface{}{"port": 2}}
+
+	result := mergeMaps(base, override)
+
+	assert.Equal(t, map[string]interface{}{"baseUrl": "b", "http": map[string]interface{}{"UseTLS": true, "port": 2}}, result)
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"regexp"
+)
+
+var envPattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// ExpandEnv replaces ${VAR} in s with the value of the environment variable VAR, or with an empty
+// string if VAR is not set, and ${VAR:-default} with the value of VAR or, if VAR is not set or is
+// empty, with default. $${ is replaced with a literal ${.
+func ExpandEnv(s string) string {
+	return expandEnv(s, os.LookupEnv)
+}
+
+func expandEnv(s string, lookupEnv func(string) (string, bool)) string {
+	return envPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if match == "$${" {
+			return "${"
+		}
+
+		groups := envPattern.FindStringSubmatch(match)
+		value, exists := lookupEnv(groups[1])
+		if len(groups[2]) > 0 && (!exists || len(value) == 0) {
+			return groups[3]
+		}
+		return value
+	})
+}
+
+// expandEnvInValues expands environment variables, as ExpandEnv does, in every string reachable
+// from v, which must be a pointer.
+func expandEnvInValues(v interface{}, lookupEnv func(string) (string, bool)) {
+	expandEnvInValue(reflect.ValueOf(v), lookupEnv)
+}
+
+func expandEnvInValue(value reflect.Value, lookupEnv func(string) (string, bool)) {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if !value.IsNil() {
+			expandEnvInValue(value.Elem(), lookupEnv)
+		}
+	case reflect.Interface:
+		if !value.IsNil() && value.CanSet() {
+			item := reflect.New(value.Elem().Type()).Elem()
+			item.Set(value.Elem())
+			expandEnvInValue(item, lookupEnv)
+			value.Set(item)
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if value.Field(i).CanSet() {
+				expandEnvInValue(value.Field(i), lookupEnv)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			expandEnvInValue(value.Index(i), lookupEnv)
+		}
+	case reflect.Map:
+		for _, key := range value.MapKeys() {
+			item := reflect.New(value.Type().Elem()).Elem()
+			item.Set(value.MapIndex(key))
+			expandEnvInValue(item, lookupEnv)
+			value.SetMapIndex(key, item)
+		}
+	case reflect.String:
+		if value.CanSet() {
+			value.SetString(expandEnv(value.String(), lookupEnv))
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+)
+
+func getFakeLookupEnv(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, exists := env[key]
+		return value, exists
+	}
+}
+
+func TestExpandEnv_ExpandsVariables_WhenSetOrDefaulted(t *testing.T) {
+	lookupEnv := getFakeLookupEnv(map[string]string{"HOST": "staging", "EMPTY": ""})
+
+	assert := assert.New(t)
+	assert.Equal("https://staging/api", expandEnv("https://${HOST}/api", lookupEnv))
+	assert.Equal("staging", expandEnv("${HOST:-localhost}", lookupEnv))
+	assert.Equal("localhost", expandEnv("${MISSING:-localhost}", lookupEnv))
+	assert.Equal("localhost", expandEnv("${EMPTY:-localhost}", lookupEnv))
+	assert.Equal("", expandEnv("${MISSING}", lookupEnv))
+	assert.Equal("${HOST} $HOST", expandEnv("$${HOST} $HOST", lookupEnv))
+}
+
+func TestExpandEnvInValues_ExpandsNestedStrings_WhenCalled(t *testing.T) {
+	lookupEnv := getFakeLookupEnv(map[string]string{"HOST": "staging", "CERT": "certs/staging.crt"})
+	apiConfig := APIConfig{
+		BaseURL: "${HOST}Api",
+		HTTP:    HTTP{CertFile: "${CERT}"},
+		Endpoints: map[string]Endpoint{
+			"getCustomers": {
+				Headers:  []Header{{Key: "X-Host", Value: "${HOST}"}},
+				Sequence: []Response{{Body: `{"host": "${HOST}"}`}},
+			},
+		},
+	}
+
+	expandEnvInValues(&apiConfig, lookupEnv)
+
+	endpoint := apiConfig.Endpoints["getCustomers"]
+	assert := assert.New(t)
+	assert.Equal("stagingApi", apiConfig.BaseURL)
+	assert.Equal("certs/staging.crt", apiConfig.HTTP.CertFile)
+	assert.Equal("staging", endpoint.Headers[0].Value)
+	assert.Equal(`{"host": "staging"}`, endpoint.Sequence[0].Body)
+}
+
+func TestPortUnmarshalTOML_ExpandsEnv_WhenGivenString(t *testing.T) {
+	var httpConfig HTTP
+
+	_, err := toml.Decode(`port = "${MOCKAPIHUB_TEST_UNSET_PORT:-5007}"`, &httpConfig)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal(Port(5007), httpConfig.Port)
+}
+
+func TestExpandEnvInValues_ExpandsStringsInJSONTable_WhenCalled(t *testing.T) {
+	lookupEnv := getFakeLookupEnv(map[string]string{"HOST": "staging"})
+	endpoint := Endpoint{
+		JSON: map[string]interface{}{
+			"host":  "${HOST}",
+			"hosts": []interface{}{"${HOST}", int64(1)},
+		},
+	}
+
+	expandEnvInValues(&endpoint, lookupEnv)
+
+	assert.Equal(t, map[string]interface{}{
+		"host":  "staging",
+		"hosts": []interface{}{"staging", int64(1)},
+	}, endpoint.JSON)
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultAppConfigPath is the path of the application configuration file used when none is given.
+	DefaultAppConfigPath = "app_config.toml"
+
+	// AppConfigPathEnv is the environment variable giving the path of the application configuration file.
+	AppConfigPathEnv = "MOCKAPIHUB_CONFIG"
+
+	// APIDirsEnv is the environment variable giving a comma-separated list of mock API directories.
+	APIDirsEnv = "MOCKAPIHUB_API_DIRS"
+
+	// PortEnv is the environment variable giving the port of the hub server.
+	PortEnv = "MOCKAPIHUB_PORT"
+
+	// LogLevelEnv is the environment variable giving the log level of the hub server.
+	LogLevelEnv = "MOCKAPIHUB_LOG_LEVEL"
+
+	// APILogLevelEnv is the environment variable giving the log level of every mock API.
+	APILogLevelEnv = "MOCKAPIHUB_API_LOG_LEVEL"
+)
+
+var logLevels = []string{"debug", "info", "warn", "error", "fatal", "panic"}
+
+// Options are settings given as command line flags or environment variables. Apart from the path
+// of the application configuration file, each option that is set overrides the application
+// configuration file. Flags take precedence over environment variables.
+type Options struct {
+	AppConfigPath string
+	APIDirs       string
+	Port          string
+	LogLevel      string
+	APILogLevel   string
+}
+
+// RegisterFlags registers a flag for each option, defaulting to the option's environment variable.
+func (options *Options) RegisterFlags(flags *flag.FlagSet, getenv func(string) string) {
+	appConfigPath := getenv(AppConfigPathEnv)
+	if len(appConfigPath) == 0 {
+		appConfigPath = DefaultAppConfigPath
+	}
+
+	options.registerFlags(flags, Options{
+		AppConfigPath: appConfigPath,
+		APIDirs:       getenv(APIDirsEnv),
+		Port:          getenv(PortEnv),
+		LogLevel:      getenv(LogLevelEnv),
+		APILogLevel:   getenv(APILogLevelEnv),
+	})
+}
+
+// RegisterSubcommandFlags registers a flag for each option on the flag set of a subcommand,
+// defaulting to the option's current value, so that options can be given both before and after
+// the subcommand. Flags the subcommand has already registered itself are left as they are.
+func (options *Options) RegisterSubcommandFlags(flags *flag.FlagSet) {
+	options.registerFlags(flags, *options)
+}
+
+func (options *Options) registerFlags(flags *flag.FlagSet, defaults Options) {
+	stringVar := func(p *string, name, value, usage string) {
+		if flags.Lookup(name) == nil {
+			flags.StringVar(p, name, value, usage)
+		}
+	}
+
+	stringVar(&options.AppConfigPath, "config", defaults.AppConfigPath, "path of the application configuration file (env "+AppConfigPathEnv+")")
+	stringVar(&options.APIDirs, "api-dirs", defaults.APIDirs, "comma-separated list of mock API directories, merged in order (env "+APIDirsEnv+")")
+	stringVar(&options.Port, "port", defaults.Port, "port of the hub server, or \""+AutoPort+"\" (env "+PortEnv+")")
+	stringVar(&options.LogLevel, "log-level", defaults.LogLevel, "log level of the hub server (env "+LogLevelEnv+")")
+	stringVar(&options.APILogLevel, "api-log-level", defaults.APILogLevel, "log level of every mock API (env "+APILogLevelEnv+")")
+}
+
+// ParseArgs parses args with the flag set, allowing flags to follow positional arguments, which are
+// returned in order.
+func ParseArgs(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	for flags.NArg() > 0 {
+		positional = append(positional, flags.Arg(0))
+		if err := flags.Parse(flags.Args()[1:]); err != nil {
+			return nil, err
+		}
+	}
+	return positional, nil
+}
+
+// Apply overrides the application configuration with the options that are set.
+func (options *Options) Apply(appConfig *AppConfig) error {
+	if dirs := splitList(options.APIDirs); len(dirs) > 0 {
+		appConfig.APIDirs = dirs
+	}
+
+	if len(options.Port) > 0 {
+		port, err := ParsePort(options.Port)
+		if err != nil {
+			return err
+		}
+		appConfig.HTTP.Port = port
+	}
+
+	if len(options.LogLevel) > 0 {
+		if err := validateLogLevel(options.LogLevel); err != nil {
+			return err
+		}
+		appConfig.Log.Level = options.LogLevel
+	}
+
+	if len(options.APILogLevel) > 0 {
+		if err := validateLogLevel(options.APILogLevel); err != nil {
+			return err
+		}
+		appConfig.APILogLevel = options.APILogLevel
+	}
+
+	return nil
+}
+
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func validateLogLevel(level string) error {
+	for _, logLevel := range logLevels {
+		if strings.EqualFold(level, logLevel) {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid log level: %s", level)
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getEnv(env map[string]string) func(string) string {
+	return func(key string) string {
+		return env[key]
+	}
+}
+
+func TestRegisterFlags_UsesEnvironment_WhenFlagsNotGiven(t *testing.T) {
+	options := Options{}
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	options.RegisterFlags(flags, getEnv(map[string]string{
+		AppConfigPathEnv: "ci/app_config.toml",
+		APIDirsEnv:       "fixtures/a,fixtures/b",
+		PortEnv:          "6000",
+	}))
+
+	err := flags.Parse([]string{"-port", "auto", "-log-level", "info"})
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal(Options{
+		AppConfigPath: "ci/app_config.toml",
+		APIDirs:       "fixtures/a,fixtures/b",
+		Port:          "auto",
+		LogLevel:      "info",
+	}, options)
+}
+
+func TestRegisterFlags_UsesDefaultAppConfigPath_WhenNotGiven(t *testing.T) {
+	options := Options{}
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	options.RegisterFlags(flags, getEnv(nil))
+
+	flags.Parse(nil)
+
+	assert.Equal(t, DefaultAppConfigPath, options.AppConfigPath)
+}
+
+func TestRegisterSubcommandFlags_KeepsOptions_WhenFlagsNotGiven(t *testing.T) {
+	options := Options{AppConfigPath: "ci/app_config.toml", LogLevel: "info"}
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	options.RegisterSubcommandFlags(flags)
+
+	err := flags.Parse([]string{"-api-dirs", "fixtures/a"})
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal(Options{
+		AppConfigPath: "ci/app_config.toml",
+		APIDirs:       "fixtures/a",
+		LogLevel:      "info",
+	}, options)
+}
+
+func TestRegisterSubcommandFlags_LeavesFlag_WhenSubcommandRegisteredIt(t *testing.T) {
+	options := Options{Port: "5000"}
+	flags := flag.NewFlagSet("new", flag.ContinueOnError)
+	apiPort := flags.String("port", "", "port of the new mock API")
+	options.RegisterSubcommandFlags(flags)
+
+	err := flags.Parse([]string{"-port", "5001", "-config", "ci/app_config.toml"})
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal("5001", *apiPort)
+	assert.Equal("5000", options.Port)
+	assert.Equal("ci/app_config.toml", options.AppConfigPath)
+}
+
+func TestParseArgs_ParsesFlags_WhenTheyFollowArguments(t *testing.T) {
+	options := Options{}
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	options.RegisterSubcommandFlags(flags)
+
+	args, err := ParseArgs(flags, []string{"ci/mockApis", "-config", "ci/app_config.toml", "fixtures"})
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal([]string{"ci/mockApis", "fixtures"}, args)
+	assert.Equal("ci/app_config.toml", options.AppConfigPath)
+}
+
+func TestApply_OverridesAppConfig_WhenOptionsSet(t *testing.T) {
+	appConfig := AppConfig{
+		HTTP:    HTTP{Port: 5000},
+		Log:     Log{Level: "debug"},
+		APIDirs: []string{"mockApis"},
+	}
+	options := Options{
+		APIDirs:     " fixtures/a, ,fixtures/b",
+		Port:        "6000",
+		LogLevel:    "warn",
+		APILogLevel: "ERROR",
+	}
+
+	err := options.Apply(&appConfig)
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal([]string{"fixtures/a", "fixtures/b"}, appConfig.APIDirs)
+	assert.Equal(Port(6000), appConfig.HTTP.Port)
+	assert.Equal("warn", appConfig.Log.Level)
+	assert.Equal("ERROR", appConfig.APILogLevel)
+}
+
+func TestApply_LeavesAppConfig_WhenOptionsNotSet(t *testing.T) {
+	appConfig := AppConfig{
+		HTTP:    HTTP{Port: 5000},
+		APIDirs: []string{"mockApis"},
+	}
+	options := Options{}
+
+	err := options.Apply(&appConfig)
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal(Port(5000), appConfig.HTTP.Port)
+	assert.Equal([]string{"mockApis"}, appConfig.APIDirs)
+}
+
+func TestApply_ReturnsError_WhenPortInvalid(t *testing.T) {
+	options := Options{Port: "-1"}
+
+	err := options.Apply(&AppConfig{})
+
+	assert.Error(t, err)
+}
+
+func TestApply_ReturnsError_WhenLogLevelInvalid(t *testing.T) {
+	options := Options{APILogLevel: "verbose"}
+
+	err := options.Apply(&AppConfig{})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,328 @@
+//Package graphql resolves GraphQL requests against a schema from fixtures, generating values for the fields that have none.
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// generatedListLength is the number of items generated for a list without a fixture.
+const generatedListLength = 2
+
+type (
+	// Request is a GraphQL request as it is sent over HTTP.
+	Request struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+
+	// Response is a GraphQL response. Data is left out of responses to requests that are not valid.
+	Response struct {
+		Data   interface{}   `json:"data,omitempty"`
+		Errors gqlerror.List `json:"errors,omitempty"`
+	}
+
+	// Fixtures are the values a request is resolved from. The fixture for an operation, keyed by
+	// operation name, is the data of the response to it or, if it has a data or errors key, the whole
+	// response. Otherwise, fields are resolved from the value of their parent, if it has one for
+	// them, or from the fixture for their type and field name, such as Types["Order"]["status"].
+	Fixtures struct {
+		Operations map[string]interface{}
+		Types      map[string]map[string]interface{}
+	}
+
+	resolver struct {
+		schema    *ast.Schema
+		fixtures  Fixtures
+		variables map[string]interface{}
+		errors    gqlerror.List
+	}
+
+	// fieldGroup is the fields of a selection set that share a response key.
+	fieldGroup struct {
+		key    string
+		fields []*ast.Field
+	}
+
+	// object is a JSON object whose keys are written in order, as GraphQL requires.
+	object []objectField
+
+	objectField struct {
+		key   string
+		value interface{}
+	}
+)
+
+// LoadSchema parses a schema written in the GraphQL schema definition language.
+func LoadSchema(name, contents string) (*ast.Schema, error) {
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: name, Input: contents})
+	if err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// Execute returns the response to a request, which is to be written as JSON.
+func Execute(schema *ast.Schema, fixtures Fixtures, request Request) interface{} {
+	document, errs := gqlparser.LoadQuery(schema, request.Query)
+	if len(errs) > 0 {
+		return Response{Errors: errs}
+	}
+
+	operation := document.Operations.ForName(request.OperationName)
+	if operation == nil {
+		if len(request.OperationName) > 0 {
+			return getErrorResponse(fmt.Sprintf("unknown operation: %s", request.OperationName))
+		}
+		return getErrorResponse("operationName is required when a request has several operations")
+	}
+
+	variables, err := validator.VariableValues(schema, operation, request.Variables)
+	if err != nil {
+		if gqlErr, ok := err.(*gqlerror.Error); ok {
+			return Response{Errors: gqlerror.List{gqlErr}}
+		}
+		return getErrorResponse(err.Error())
+	}
+
+	if fixture, exists := fixtures.Operations[operation.Name]; exists && len(operation.Name) > 0 {
+		if response, ok := fixture.(map[string]interface{}); ok {
+			if _, hasData := response["data"]; hasData {
+				return response
+			}
+			if _, hasErrors := response["errors"]; hasErrors {
+				return response
+			}
+		}
+		return Response{Data: fixture}
+	}
+
+	var root *ast.Definition
+	switch operation.Operation {
+	case ast.Query:
+		root = schema.Query
+	case ast.Mutation:
+		root = schema.Mutation
+	default:
+		return getErrorResponse(fmt.Sprintf("%s operations are not supported", operation.Operation))
+	}
+
+	res := &resolver{
+		schema:    schema,
+		fixtures:  fixtures,
+		variables: variables,
+	}
+	data := res.resolveSelectionSet(operation.SelectionSet, root, nil, 0)
+	return Response{Data: data, Errors: res.errors}
+}
+
+func getErrorResponse(message string) Response {
+	return Response{Errors: gqlerror.List{{Message: message}}}
+}
+
+func (res *resolver) resolveSelectionSet(selectionSet ast.SelectionSet, def *ast.Definition, source interface{}, index int) object {
+	var result object
+	for _, group := range res.collectFields(selectionSet, def, nil) {
+		result = append(result, objectField{
+			key:   group.key,
+			value: res.resolveField(group, def, source, index),
+		})
+	}
+	return result
+}
+
+// collectFields groups the fields of a selection set, including those of the fragments that apply
+// to the type, by response key.
+func (res *resolver) collectFields(selectionSet ast.SelectionSet, def *ast.Definition, groups []*fieldGroup) []*fieldGroup {
+	for _, selection := range selectionSet {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if res.isSkipped(selection.Directives) {
+				continue
+			}
+			key := selection.Alias
+			if len(key) == 0 {
+				key = selection.Name
+			}
+			group := findGroup(groups, key)
+			if group == nil {
+				group = &fieldGroup{key: key}
+				groups = append(groups, group)
+			}
+			group.fields = append(group.fields, selection)
+		case *ast.FragmentSpread:
+			if !res.isSkipped(selection.Directives) && res.appliesTo(selection.Definition.TypeCondition, def) {
+				groups = res.collectFields(selection.Definition.SelectionSet, def, groups)
+			}
+		case *ast.InlineFragment:
+			if !res.isSkipped(selection.Directives) && (len(selection.TypeCondition) == 0 || res.appliesTo(selection.TypeCondition, def)) {
+				groups = res.collectFields(selection.SelectionSet, def, groups)
+			}
+		}
+	}
+	return groups
+}
+
+func findGroup(groups []*fieldGroup, key string) *fieldGroup {
+	for _, group := range groups {
+		if group.key == key {
+			return group
+		}
+	}
+	return nil
+}
+
+func (res *resolver) isSkipped(directives ast.DirectiveList) bool {
+	if skip := directives.ForName("skip"); skip != nil && skip.ArgumentMap(res.variables)["if"] == true {
+		return true
+	}
+	if include := directives.ForName("include"); include != nil && include.ArgumentMap(res.variables)["if"] == false {
+		return true
+	}
+	return false
+}
+
+// appliesTo returns whether a fragment with the type condition applies to an object of the type.
+func (res *resolver) appliesTo(typeCondition string, def *ast.Definition) bool {
+	if typeCondition == def.Name {
+		return true
+	}
+	condition := res.schema.Types[typeCondition]
+	if condition == nil {
+		return false
+	}
+	for _, possible := range res.schema.GetPossibleTypes(condition) {
+		if possible.Name == def.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func (res *resolver) resolveField(group *fieldGroup, parent *ast.Definition, source interface{}, index int) interface{} {
+	field := group.fields[0]
+	if field.Name == "__typename" {
+		return parent.Name
+	}
+	if strings.HasPrefix(field.Name, "__") {
+		res.errors = append(res.errors, &gqlerror.Error{
+			Message:   "introspection is not supported by mock GraphQL endpoints",
+			Locations: []gqlerror.Location{{Line: field.Position.Line, Column: field.Position.Column}},
+		})
+		return nil
+	}
+
+	value, found := getFixture(source, field.Name)
+	if !found {
+		value, found = res.fixtures.Types[parent.Name][field.Name]
+	}
+
+	var selectionSet ast.SelectionSet
+	for _, f := range group.fields {
+		selectionSet = append(selectionSet, f.SelectionSet...)
+	}
+	return res.complete(field.Definition.Type, selectionSet, field.Name, value, found, index)
+}
+
+func getFixture(source interface{}, name string) (interface{}, bool) {
+	fields, ok := source.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	value, exists := fields[name]
+	return value, exists
+}
+
+// complete shapes a value, or generates one if none was found, to match the field's type.
+func (res *resolver) complete(fieldType *ast.Type, selectionSet ast.SelectionSet, name string, value interface{}, found bool, index int) interface{} {
+	if found && value == nil {
+		return nil
+	}
+
+	if fieldType.Elem != nil {
+		items, ok := value.([]interface{})
+		if !found || !ok {
+			items = make([]interface{}, generatedListLength)
+			found = false
+		}
+		result := make([]interface{}, len(items))
+		for i, item := range items {
+			result[i] = res.complete(fieldType.Elem, selectionSet, name, item, found, i)
+		}
+		return result
+	}
+
+	def := res.schema.Types[fieldType.NamedType]
+	switch def.Kind {
+	case ast.Scalar:
+		if found {
+			return value
+		}
+		return generateScalar(def.Name, name, index)
+	case ast.Enum:
+		if found || len(def.EnumValues) == 0 {
+			return value
+		}
+		return def.EnumValues[0].Name
+	case ast.Interface, ast.Union:
+		return res.resolveSelectionSet(selectionSet, res.getConcreteType(def, value), value, index)
+	default:
+		return res.resolveSelectionSet(selectionSet, def, value, index)
+	}
+}
+
+// getConcreteType returns the object type of a value of an interface or union type: the type named
+// by the value's __typename, if it has one, or else the first possible type.
+func (res *resolver) getConcreteType(def *ast.Definition, value interface{}) *ast.Definition {
+	if typeName, ok := getFixture(value, "__typename"); ok {
+		if name, ok := typeName.(string); ok && res.schema.Types[name] != nil {
+			return res.schema.Types[name]
+		}
+	}
+	possible := res.schema.GetPossibleTypes(def)
+	if len(possible) == 0 {
+		return def
+	}
+	return possible[0]
+}
+
+// isTimestampName reports whether a field's name suggests it holds a time, as createdAt, updated_at,
+// birthDate and startTime do. Names that merely end in "at", such as format, do not.
+func isTimestampName(fieldName, lowerName string) bool {
+	return strings.HasSuffix(fieldName, "At") || strings.HasSuffix(lowerName, "_at") ||
+		strings.Contains(lowerName, "date") || strings.Contains(lowerName, "time")
+}
+
+// generateScalar returns a plausible value for a field of a scalar type, varying with the index of
+// the object the field belongs to.
+func generateScalar(typeName, fieldName string, index int) interface{} {
+	number := index + 1
+	switch typeName {
+	case "Int":
+		return number
+	case "Float":
+		return float64(number) + 0.5
+	case "Boolean":
+		return true
+	case "ID":
+		return strconv.Itoa(number)
+	}
+
+	lowerName := strings.ToLower(fieldName)
+	switch {
+	case strings.Contains(lowerName, "email"):
+		return fmt.Sprintf("user%d@example.com", number)
+	case strings.Contains(lowerName, "url"):
+		return fmt.Sprintf("https://example.com/%s/%d", lowerName, number)
+	case isTimestampName(fieldName, lowerName):
+		return fmt.Sprintf("2020-01-%02dT00:00:00Z", (number-1)%28+1)
+	}
+	return fmt.Sprintf("%s %d", fieldName, number)
+}
@@ -0,0 +1,176 @@
+package graphql
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const ordersSchema = `
+type Query {
+	order(id: ID!): Order
+	orders: [Order!]!
+	search(text: String!): [SearchResult!]!
+	node(id: ID!): Node
+}
+
+type Mutation {
+	cancelOrder(id: ID!): Order
+}
+
+interface Node {
+	id: ID!
+}
+
+enum Status {
+	PENDING
+	SHIPPED
+}
+
+type Order implements Node {
+	id: ID!
+	total: Float!
+	quantity: Int!
+	status: Status!
+	customerEmail: String
+	createdAt: String
+	note: String
+	customer: Customer
+}
+
+type Customer implements Node {
+	id: ID!
+	name: String!
+}
+
+union SearchResult = Order | Customer
+`
+
+func getSchema(t *testing.T) *ast.Schema {
+	schema, err := LoadSchema("orders.graphql", ordersSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func execute(t *testing.T, fixtures Fixtures, request Request) string {
+	contents, err := json.Marshal(Execute(getSchema(t), fixtures, request))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(contents)
+}
+
+func TestExecute_GeneratesValues_WhenNoFixtures(t *testing.T) {
+	result := execute(t, Fixtures{}, Request{Query: `{ orders { id total quantity status customerEmail createdAt note } }`})
+
+	assert.JSONEq(t, `{"data": {"orders": [
+		{"id": "1", "total": 1.5, "quantity": 1, "status": "PENDING", "customerEmail": "user1@example.com", "createdAt": "2020-01-01T00:00:00Z", "note": "note 1"},
+		{"id": "2", "total": 2.5, "quantity": 2, "status": "PENDING", "customerEmail": "user2@example.com", "createdAt": "2020-01-02T00:00:00Z", "note": "note 2"}
+	]}}`, result)
+}
+
+func TestExecute_ResolvesFromTypeAndParentFixtures_WhenProvided(t *testing.T) {
+	fixtures := Fixtures{
+		Types: map[string]map[string]interface{}{
+			"Query": {"order": map[string]interface{}{"id": "A-1", "customer": map[string]interface{}{"name": "Ada"}}},
+			"Order": {"status": "SHIPPED", "note": nil},
+		},
+	}
+
+	result := execute(t, fixtures, Request{Query: `{ order(id: "A-1") { id status note customer { id name } } }`})
+
+	assert.JSONEq(t, `{"data": {"order": {"id": "A-1", "status": "SHIPPED", "note": null, "customer": {"id": "1", "name": "Ada"}}}}`, result)
+}
+
+func TestExecute_ReturnsOperationFixture_WhenOperationNamed(t *testing.T) {
+	fixtures := Fixtures{
+		Operations: map[string]interface{}{
+			"GetOrder":    map[string]interface{}{"order": map[string]interface{}{"id": "7"}},
+			"CancelOrder": map[string]interface{}{"errors": []interface{}{map[string]interface{}{"message": "order already shipped"}}},
+		},
+	}
+
+	data := execute(t, fixtures, Request{Query: `query GetOrder { order(id: "7") { id } }`})
+	errs := execute(t, fixtures, Request{
+		Query:         `query GetOrder { order(id: "7") { id } } mutation CancelOrder { cancelOrder(id: "7") { id } }`,
+		OperationName: "CancelOrder",
+	})
+
+	assert := assert.New(t)
+	assert.JSONEq(`{"data": {"order": {"id": "7"}}}`, data)
+	assert.JSONEq(`{"errors": [{"message": "order already shipped"}]}`, errs)
+}
+
+func TestExecute_ReturnsErrors_WhenQueryInvalid(t *testing.T) {
+	result := execute(t, Fixtures{}, Request{Query: "{\n  orders { id cost }\n}"})
+
+	var response map[string]interface{}
+	json.Unmarshal([]byte(result), &response)
+	assert := assert.New(t)
+	assert.NotContains(response, "data")
+	assert.Contains(result, `"message":"Cannot query field \"cost\" on type \"Order\"."`)
+	assert.Contains(result, `"locations":[{"line":2,"column":15}]`)
+}
+
+func TestExecute_ReturnsError_WhenSeveralOperationsAndNoName(t *testing.T) {
+	result := execute(t, Fixtures{}, Request{Query: `query A { orders { id } } query B { orders { id } }`})
+
+	assert.JSONEq(t, `{"errors": [{"message": "operationName is required when a request has several operations"}]}`, result)
+}
+
+func TestExecute_ResolvesFragmentsAndAbstractTypes_WhenQueried(t *testing.T) {
+	fixtures := Fixtures{
+		Types: map[string]map[string]interface{}{
+			"Query": {"search": []interface{}{
+				map[string]interface{}{"__typename": "Customer", "name": "Ada"},
+				map[string]interface{}{"__typename": "Order", "id": "9"},
+			}},
+		},
+	}
+	query := `
+		query Search($withNames: Boolean!) {
+			search(text: "a") {
+				__typename
+				... on Order { orderID: id }
+				...customerFields
+			}
+			node(id: "1") { id }
+		}
+		fragment customerFields on Customer { name @include(if: $withNames) id }`
+
+	result := execute(t, fixtures, Request{Query: query, Variables: map[string]interface{}{"withNames": false}})
+
+	assert.Equal(t, `{"data":{"search":[{"__typename":"Customer","id":"1"},{"__typename":"Order","orderID":"9"}],"node":{"id":"1"}}}`, result)
+}
+
+func TestExecute_ReturnsError_WhenVariableMissing(t *testing.T) {
+	result := execute(t, Fixtures{}, Request{Query: `query GetOrder($id: ID!) { order(id: $id) { id } }`})
+
+	assert.Contains(t, result, `"errors"`)
+	assert.NotContains(t, result, `"data"`)
+}
+
+func TestExecute_ReturnsError_WhenIntrospectionQueried(t *testing.T) {
+	result := execute(t, Fixtures{}, Request{Query: `{ __schema { queryType { name } } }`})
+
+	assert.Contains(t, result, "introspection is not supported by mock GraphQL endpoints")
+}
+
+func TestGenerateScalar_GeneratesTimestamp_WhenNameSuggestsTime(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("2020-01-01T00:00:00Z", generateScalar("String", "createdAt", 0))
+	assert.Equal("2020-01-28T00:00:00Z", generateScalar("String", "updated_at", 27))
+	assert.Equal("2020-01-01T00:00:00Z", generateScalar("String", "birthDate", 28))
+	assert.Equal("format 1", generateScalar("String", "format", 0))
+	assert.Equal("seat 2", generateScalar("String", "seat", 1))
+}
+
+func TestLoadSchema_ReturnsError_WhenSchemaInvalid(t *testing.T) {
+	_, err := LoadSchema("broken.graphql", "type Query { orders: [Order] }")
+
+	assert.Error(t, err)
+}
@@ -27,7 +27,7 @@ func GetFakeFileInfoAndCollection(dir, file string) (os.FileInfo, []os.FileInfo)
 func GetFakeAPIConfig(port int) *config.APIConfig {
 	return &config.APIConfig{
 		HTTP: config.HTTP{
-			Port: port,
+			Port: config.Port(port),
 		},
 	}
 }
@@ -36,6 +36,7 @@ func GetFakeAPIConfig(port int) *config.APIConfig {
 func GetFakeAppConfig(certFile, keyFile string) *config.AppConfig {
 	return &config.AppConfig{
 		HTTP: config.HTTP{
+			Port:     5000,
 			CertFile: certFile,
 			KeyFile:  keyFile,
 		},
@@ -0,0 +1,100 @@
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathStep is one step of a JSON path: a key of an object or, if key is empty, an index of an array.
+type pathStep struct {
+	key   string
+	index int
+}
+
+// Lookup returns the value at a JSON path in a JSON document, and whether the document has a value
+// there. A path starts with $, the document, followed by keys, as .key or ['key'], and array
+// indexes, as [0]; for example, $.orders[0]['order id'].
+func Lookup(contents []byte, path string) (interface{}, bool, error) {
+	steps, err := parsePath(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(contents, &value); err != nil {
+		return nil, false, err
+	}
+
+	for _, step := range steps {
+		if len(step.key) > 0 {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, false, nil
+			}
+			if value, ok = object[step.key]; !ok {
+				return nil, false, nil
+			}
+			continue
+		}
+
+		array, ok := value.([]interface{})
+		if !ok || step.index >= len(array) {
+			return nil, false, nil
+		}
+		value = array[step.index]
+	}
+	return value, true, nil
+}
+
+// ValidatePath returns an error if path is not a JSON path that Lookup supports.
+func ValidatePath(path string) error {
+	_, err := parsePath(path)
+	return err
+}
+
+func parsePath(path string) ([]pathStep, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("JSON path must start with $: %s", path)
+	}
+
+	var steps []pathStep
+	rest := path[1:]
+	for len(rest) > 0 {
+		switch {
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if len(key) == 0 {
+				return nil, fmt.Errorf("JSON path has an empty key: %s", path)
+			}
+			steps = append(steps, pathStep{key: key})
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "['"):
+			end := strings.Index(rest, "']")
+			if end < 0 || end == 2 {
+				return nil, fmt.Errorf("JSON path has an unterminated or empty key: %s", path)
+			}
+			steps = append(steps, pathStep{key: rest[2:end]})
+			rest = rest[end+2:]
+		case rest[0] == '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("JSON path has an unterminated index: %s", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("JSON path has an invalid index: %s", path)
+			}
+			steps = append(steps, pathStep{index: index})
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid JSON path: %s", path)
+		}
+	}
+	return steps, nil
+}
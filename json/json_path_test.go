@@ -0,0 +1,51 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var orders = []byte(`{"action": "subscribe", "orders": [{"order id": 7, "symbol": "ACME"}]}`)
+
+func TestLookup_ReturnsValue_WhenPathExists(t *testing.T) {
+	action, actionExists, actionErr := Lookup(orders, "$.action")
+	id, idExists, idErr := Lookup(orders, "$.orders[0]['order id']")
+	document, documentExists, _ := Lookup(orders, "$")
+
+	assert := assert.New(t)
+	assert.Nil(actionErr)
+	assert.Nil(idErr)
+	assert.True(actionExists)
+	assert.True(idExists)
+	assert.True(documentExists)
+	assert.Equal("subscribe", action)
+	assert.Equal(float64(7), id)
+	assert.IsType(map[string]interface{}{}, document)
+}
+
+func TestLookup_ReturnsFalse_WhenPathDoesNotExist(t *testing.T) {
+	assert := assert.New(t)
+	for _, path := range []string{"$.missing", "$.orders[1]", "$.action[0]", "$.orders.symbol"} {
+		_, exists, err := Lookup(orders, path)
+		assert.Nil(err, path)
+		assert.False(exists, path)
+	}
+}
+
+func TestLookup_ReturnsError_WhenContentsNotJSON(t *testing.T) {
+	_, exists, err := Lookup([]byte("subscribe"), "$.action")
+
+	assert := assert.New(t)
+	assert.Error(err)
+	assert.False(exists)
+}
+
+func TestValidatePath_ReturnsError_WhenPathInvalid(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(ValidatePath("$.orders[0].symbol"))
+	assert.Error(ValidatePath("orders"))
+	assert.Error(ValidatePath("$..orders"))
+	assert.Error(ValidatePath("$.orders[first]"))
+	assert.Error(ValidatePath("$['orders"))
+}
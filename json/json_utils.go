@@ -25,13 +25,21 @@ func GetJSON(filePath string, file wrapper.IFileOps) ([]byte, error) {
 		return nil, err
 	}
 
-	if !isValidJSON(bytes) {
-		return nil, errors.New("invalid JSON")
+	if err := ValidateJSON(bytes); err != nil {
+		return nil, err
 	}
 
 	return bytes, nil
 }
 
+// ValidateJSON returns an error if bytes are not valid JSON.
+func ValidateJSON(bytes []byte) error {
+	if !isValidJSON(bytes) {
+		return errors.New("invalid JSON")
+	}
+	return nil
+}
+
 func isValidJSON(bytes []byte) bool {
 	var js json.RawMessage
 	return json.Unmarshal(bytes, &js) == nil
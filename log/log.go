@@ -65,6 +65,21 @@ const (
 
 	// ResponseHeadersField is the name of the log field denoting the response headers.
 	ResponseHeadersField = "responseHeaders"
+
+	// VirtualHostField is the name of the log field denoting the host name a mock API or request is for.
+	VirtualHostField = "virtualHost"
+
+	// SchemaField is the name of the log field denoting a JSON schema file.
+	SchemaField = "schema"
+
+	// ViolationsField is the name of the log field denoting the ways a document violates a JSON schema.
+	ViolationsField = "violations"
+
+	// FrameField is the name of the log field denoting the contents of a WebSocket frame.
+	FrameField = "frame"
+
+	// GRPCMessageField is the name of the log field denoting the contents of a gRPC message.
+	GRPCMessageField = "grpcMessage"
 )
 
 // NewLogger returns a new instance of a logger.
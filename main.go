@@ -1,5 +1,15 @@
 /*
-Package main is the main entry point for the application. It requires configuration in a file called app_config.toml.
+Package main is the main entry point for the application. It requires configuration in a file called app_config.toml,
+or in the file given by the -config flag or the MOCKAPIHUB_CONFIG environment variable, which can also be in YAML or
+JSON format. The flags -api-dirs, -port, -log-level and -api-log-level, and the environment variables
+MOCKAPIHUB_API_DIRS, MOCKAPIHUB_PORT, MOCKAPIHUB_LOG_LEVEL and MOCKAPIHUB_API_LOG_LEVEL, override the configuration.
+
+Running "mockapihub validate [dir...]" checks the configuration, and the mock APIs in the directories given or else
+in the configured directories, without starting the hub. Each issue found is printed as file:line: message, and the
+exit status is non-zero if there are any.
+
+Running "mockapihub new <name> -port N [-base-url URL]" creates the directory of a new mock API, named with the Api
+suffix, containing a commented configuration file and a sample data file.
 
 Example configuration:
 
@@ -24,17 +34,20 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 
 	"github.com/wcsanders1/MockApiHub/config"
 	"github.com/wcsanders1/MockApiHub/manager"
-
-	"github.com/BurntSushi/toml"
+	"github.com/wcsanders1/MockApiHub/scaffold"
+	"github.com/wcsanders1/MockApiHub/validate"
 )
 
 func main() {
 
+	var options config.Options
 	hubFlags := flag.NewFlagSet("hubFlags", flag.ExitOnError)
 	showVersion := hubFlags.Bool("v", false, "application version")
+	options.RegisterFlags(hubFlags, os.Getenv)
 	hubFlags.Parse(os.Args[1:])
 	hubFlags.Visit(func(f *flag.Flag) {
 		if f.Name == "v" {
@@ -47,19 +60,38 @@ func main() {
 		return
 	}
 
-	var appConfig config.AppConfig
-	if _, err := toml.DecodeFile("app_config.toml", &appConfig); err != nil {
+	if hubFlags.Arg(0) == "validate" {
+		validateFlags := flag.NewFlagSet("validate", flag.ExitOnError)
+		options.RegisterSubcommandFlags(validateFlags)
+		dirs, _ := config.ParseArgs(validateFlags, hubFlags.Args()[1:])
+		if len(dirs) > 0 {
+			options.APIDirs = strings.Join(dirs, ",")
+		}
+		os.Exit(runValidate(options))
+	}
+
+	if hubFlags.Arg(0) == "new" {
+		os.Exit(runNew(options, hubFlags.Args()[1:]))
+	}
+
+	appConfig, err := config.NewConfigManager().GetAppConfig(options.AppConfigPath)
+	if err != nil {
+		fmt.Println(err)
+		panic(err)
+	}
+
+	if err := options.Apply(appConfig); err != nil {
 		fmt.Println(err)
 		panic(err)
 	}
 
-	mgr, err := manager.NewManager(&appConfig)
+	mgr, err := manager.NewManager(appConfig)
 	if err != nil {
 		fmt.Println(err)
 		panic(err)
 	}
 
-	shutdown := make(chan os.Signal)
+	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt)
 
 	go func() {
@@ -72,3 +104,64 @@ func main() {
 	<-shutdown
 	mgr.StopMockAPIHub()
 }
+
+func runValidate(options config.Options) int {
+	issues := validate.NewValidator().Validate(options)
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+
+	if len(issues) > 0 {
+		fmt.Printf("%d issue(s) found\n", len(issues))
+		return 1
+	}
+
+	fmt.Println("configuration is valid")
+	return 0
+}
+
+func runNew(options config.Options, args []string) int {
+	newFlags := flag.NewFlagSet("new", flag.ExitOnError)
+	port := newFlags.String("port", "", "port of the new mock API, or \""+config.AutoPort+"\"")
+	baseURL := newFlags.String("base-url", "", "base URL of the new mock API; defaults to its directory name")
+	options.RegisterSubcommandFlags(newFlags)
+	names, _ := config.ParseArgs(newFlags, args)
+	var name string
+	if len(names) > 0 {
+		name = names[0]
+	}
+
+	if len(name) == 0 || len(*port) == 0 || len(names) > 1 {
+		fmt.Println("usage: mockapihub new <name> -port N [-base-url URL]")
+		return 2
+	}
+
+	apiPort, err := config.ParsePort(*port)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	appConfig, err := config.NewConfigManager().GetAppConfig(options.AppConfigPath)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	if err := options.Apply(appConfig); err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	dir, err := scaffold.NewScaffolder().Create(appConfig, scaffold.APIOptions{
+		Name:    name,
+		Port:    apiPort,
+		BaseURL: *baseURL,
+	})
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	fmt.Printf("created mock API in %s\n", dir)
+	return 0
+}
@@ -2,24 +2,32 @@ package manager
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/wcsanders1/MockApiHub/api"
 	"github.com/wcsanders1/MockApiHub/config"
 	"github.com/wcsanders1/MockApiHub/log"
 	"github.com/wcsanders1/MockApiHub/ref"
+
+	"github.com/sirupsen/logrus"
 )
 
 type apiDisplay struct {
 	BaseURL   string
 	Endpoints map[string]config.Endpoint
 	Port      int
+	Protocol  string `json:",omitempty"`
 }
 
 const (
 	refreshAPIsPath = "refresh-all-mock-apis"
 	showAllAPIsPath = "show-all-registered-mock-apis"
+	showAPIPath     = "show-registered-mock-api"
+	showFramesPath  = "show-received-websocket-frames"
+	apiNameParam    = "name"
 )
 
 func (mgr *Manager) refreshMockAPIs(w http.ResponseWriter, r *http.Request) {
@@ -28,15 +36,41 @@ func (mgr *Manager) refreshMockAPIs(w http.ResponseWriter, r *http.Request) {
 
 	mgr.shutDownMockAPIs()
 	mgr.apis = make(map[string]api.IAPI)
+	mgr.sharedServers = make(map[int]api.ISharedServer)
 	if err := mgr.loadMockAPIs(); err != nil {
 		contextLogger.WithError(err).Error("error loading mock APIs")
 		return
 	}
 
 	mgr.startMockAPIs()
+	mgr.writePortsFile()
 	msg := "successfully refreshed mock apis"
 	w.Write([]byte(msg))
 	contextLogger.Debug(msg)
+
+	if violations := mgr.getResponseSchemaViolations(); len(violations) > 0 {
+		contextLogger.WithField(log.ViolationsField, violations).Warn("responses served by mock APIs do not match their response schemas")
+		w.Write([]byte("\n\nresponses that do not match their response schemas:\n" + strings.Join(violations, "\n")))
+	}
+}
+
+func (mgr *Manager) getResponseSchemaViolations() []string {
+	var violations []string
+	for apiName, api := range mgr.apis {
+		endpoints := api.GetEndpoints()
+		for endpointName, endpointViolations := range api.GetResponseSchemaViolations() {
+			for _, violation := range endpointViolations {
+				endpoint := fmt.Sprintf("%s/%s", apiName, endpointName)
+				if file := endpoints[endpointName].File; len(file) > 0 {
+					endpoint = fmt.Sprintf("%s (%s)", endpoint, file)
+				}
+				violations = append(violations, fmt.Sprintf("%s: %s", endpoint, violation))
+			}
+		}
+	}
+
+	sort.Strings(violations)
+	return violations
 }
 
 func (mgr *Manager) showRegisteredMockAPIs(w http.ResponseWriter, r *http.Request) {
@@ -49,6 +83,7 @@ func (mgr *Manager) showRegisteredMockAPIs(w http.ResponseWriter, r *http.Reques
 			BaseURL:   api.GetBaseURL(),
 			Port:      api.GetPort(),
 			Endpoints: api.GetEndpoints(),
+			Protocol:  api.GetProtocol(),
 		}
 	}
 
@@ -62,6 +97,40 @@ func (mgr *Manager) showRegisteredMockAPIs(w http.ResponseWriter, r *http.Reques
 	contextLogger.WithField("registeredAPIs", apis).Debug("successfully showed all registered mock APIs")
 }
 
+func (mgr *Manager) showRegisteredMockAPI(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get(apiNameParam)
+	contextLogger := mgr.log.WithFields(logrus.Fields{
+		log.FuncField: ref.GetFuncName(),
+		"apiName":     name,
+	})
+	contextLogger.Debug("showing registered mock API")
+
+	for apiName, api := range mgr.apis {
+		if !strings.EqualFold(apiName, name) {
+			continue
+		}
+
+		apiJSON, err := json.Marshal(apiDisplay{
+			BaseURL:   api.GetBaseURL(),
+			Port:      api.GetPort(),
+			Endpoints: api.GetEndpoints(),
+			Protocol:  api.GetProtocol(),
+		})
+		if err != nil {
+			contextLogger.WithError(err).Error("error displaying mock API")
+			return
+		}
+
+		w.Write(apiJSON)
+		contextLogger.Debug("successfully showed registered mock API")
+		return
+	}
+
+	contextLogger.Warn("mock API not found")
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte("mock API not found"))
+}
+
 func (mgr *Manager) registerHubAPIHandlers() {
 	contextLogger := mgr.log.WithField(log.FuncField, ref.GetFuncName())
 	contextLogger.Debug("registering hub API handlers")
@@ -72,6 +141,37 @@ func (mgr *Manager) registerHubAPIHandlers() {
 
 	mgr.hubAPIHandlers[http.MethodPost][strings.ToLower(refreshAPIsPath)] = mgr.refreshMockAPIs
 	mgr.hubAPIHandlers[http.MethodGet][strings.ToLower(showAllAPIsPath)] = mgr.showRegisteredMockAPIs
+	mgr.hubAPIHandlers[http.MethodGet][strings.ToLower(showAPIPath)] = mgr.showRegisteredMockAPI
+	mgr.hubAPIHandlers[http.MethodGet][strings.ToLower(showFramesPath)] = mgr.showReceivedWebSocketFrames
 
 	contextLogger.Debug("successfully registered hub API handlers")
 }
+
+func (mgr *Manager) showReceivedWebSocketFrames(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get(apiNameParam)
+	contextLogger := mgr.log.WithFields(logrus.Fields{
+		log.FuncField: ref.GetFuncName(),
+		"apiName":     name,
+	})
+	contextLogger.Debug("showing WebSocket frames received by mock API")
+
+	for apiName, api := range mgr.apis {
+		if !strings.EqualFold(apiName, name) {
+			continue
+		}
+
+		framesJSON, err := json.Marshal(api.GetReceivedFrames())
+		if err != nil {
+			contextLogger.WithError(err).Error("error displaying WebSocket frames")
+			return
+		}
+
+		w.Write(framesJSON)
+		contextLogger.Debug("successfully showed WebSocket frames received by mock API")
+		return
+	}
+
+	contextLogger.Warn("mock API not found")
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte("mock API not found"))
+}
@@ -5,14 +5,17 @@ import (
 	"net/http"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/wcsanders1/MockApiHub/api"
 	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/constants"
 	"github.com/wcsanders1/MockApiHub/fake"
 	"github.com/wcsanders1/MockApiHub/helper"
 	"github.com/wcsanders1/MockApiHub/log"
 	"github.com/wcsanders1/MockApiHub/wrapper"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -28,6 +31,7 @@ func TestShowRegisteredMockAPIs_WritesJSON_WhenCalled(t *testing.T) {
 	fakeAPI := new(api.FakeAPI)
 	fakeAPI.On("GetBaseURL").Return("testURL")
 	fakeAPI.On("GetPort").Return(4000)
+	fakeAPI.On("GetProtocol").Return("")
 	fakeAPI.On("GetEndpoints").Return(endpoints)
 	fakeAPIs := make(map[string]api.IAPI)
 	fakeAPIs["fakeAPI"] = fakeAPI
@@ -56,11 +60,12 @@ func TestRefreshMockAPIs_WritesToResponse_WhenSuccessful(t *testing.T) {
 	fileOps.On("ReadDir", mock.AnythingOfType("string")).Return(fileInfoCollection, nil)
 	apiConfig := helper.GetFakeAPIConfig(4000)
 	configManager := new(config.FakeManager)
-	configManager.On("GetAPIConfig", mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
+	configManager.On("GetAPIConfig", mock.AnythingOfType("string"), mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
 	mgr := Manager{
 		log:           log.GetFakeLogger(),
 		file:          fileOps,
 		configManager: configManager,
+		apiDirs:       []string{constants.APIDir},
 		config:        helper.GetFakeAppConfig("certFile", "keyFile"),
 	}
 	w := new(fake.ResponseWriter)
@@ -81,11 +86,12 @@ func TestRefreshMockAPIs_DoesNotWriteToResponse_WhenFails(t *testing.T) {
 	fileOps.On("ReadDir", mock.AnythingOfType("string")).Return(fileInfoCollection, errors.New(""))
 	apiConfig := helper.GetFakeAPIConfig(4000)
 	configManager := new(config.FakeManager)
-	configManager.On("GetAPIConfig", mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
+	configManager.On("GetAPIConfig", mock.AnythingOfType("string"), mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
 	mgr := Manager{
 		log:           log.GetFakeLogger(),
 		file:          fileOps,
 		configManager: configManager,
+		apiDirs:       []string{constants.APIDir},
 		config:        helper.GetFakeAppConfig("certFile", "keyFile"),
 	}
 	w := new(fake.ResponseWriter)
@@ -96,3 +102,117 @@ func TestRefreshMockAPIs_DoesNotWriteToResponse_WhenFails(t *testing.T) {
 
 	w.AssertNotCalled(t, "Write", mock.Anything)
 }
+
+func TestGetResponseSchemaViolations_ReturnsSortedViolations_WhenAPIsHaveViolations(t *testing.T) {
+	endpoints := map[string]config.Endpoint{
+		"getCustomers": config.Endpoint{
+			File: "customers.json",
+		},
+	}
+	fakeAPI := new(api.FakeAPI)
+	fakeAPI.On("GetEndpoints").Return(endpoints)
+	fakeAPI.On("GetResponseSchemaViolations").Return(map[string][]string{
+		"getCustomers": []string{"name: name is required", "age: Invalid type."},
+	})
+	mgr := Manager{
+		apis: map[string]api.IAPI{"customersApi": fakeAPI},
+		log:  log.GetFakeLogger(),
+	}
+
+	result := mgr.getResponseSchemaViolations()
+
+	assert := assert.New(t)
+	assert.Equal([]string{
+		"customersApi/getCustomers (customers.json): age: Invalid type.",
+		"customersApi/getCustomers (customers.json): name: name is required",
+	}, result)
+}
+
+func TestShowRegisteredMockAPI_WritesJSON_WhenAPIRegistered(t *testing.T) {
+	fakeAPI := new(api.FakeAPI)
+	fakeAPI.On("GetBaseURL").Return("testURL")
+	fakeAPI.On("GetPort").Return(5001)
+	fakeAPI.On("GetProtocol").Return("")
+	fakeAPI.On("GetEndpoints").Return(map[string]config.Endpoint{})
+	mgr := Manager{
+		apis: map[string]api.IAPI{"customersApi": fakeAPI},
+		log:  log.GetFakeLogger(),
+	}
+	w := new(fake.ResponseWriter)
+	w.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
+	request, _ := http.NewRequest("GET", "show-registered-mock-api?name=CustomersApi", nil)
+
+	mgr.showRegisteredMockAPI(w, request)
+
+	w.AssertCalled(t, "Write", []byte(`{"BaseURL":"testURL","Endpoints":{},"Port":5001}`))
+	w.AssertNotCalled(t, "WriteHeader", mock.Anything)
+}
+
+func TestShowRegisteredMockAPI_WritesProtocol_WhenAPIUsesGRPC(t *testing.T) {
+	fakeAPI := new(api.FakeAPI)
+	fakeAPI.On("GetBaseURL").Return("")
+	fakeAPI.On("GetPort").Return(5002)
+	fakeAPI.On("GetProtocol").Return("grpc")
+	fakeAPI.On("GetEndpoints").Return(map[string]config.Endpoint{})
+	mgr := Manager{
+		apis: map[string]api.IAPI{"ordersApi": fakeAPI},
+		log:  log.GetFakeLogger(),
+	}
+	w := new(fake.ResponseWriter)
+	w.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
+	request, _ := http.NewRequest("GET", "show-registered-mock-api?name=ordersApi", nil)
+
+	mgr.showRegisteredMockAPI(w, request)
+
+	w.AssertCalled(t, "Write", []byte(`{"BaseURL":"","Endpoints":{},"Port":5002,"Protocol":"grpc"}`))
+}
+
+func TestShowReceivedWebSocketFrames_WritesFrames_WhenAPIRegistered(t *testing.T) {
+	received := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fakeAPI := new(api.FakeAPI)
+	fakeAPI.On("GetReceivedFrames").Return(map[string][]api.ReceivedFrame{
+		"tradingFeed": []api.ReceivedFrame{{Time: received, Data: "ping"}},
+	})
+	mgr := Manager{
+		apis: map[string]api.IAPI{"tradingApi": fakeAPI},
+		log:  log.GetFakeLogger(),
+	}
+	w := new(fake.ResponseWriter)
+	w.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
+	request, _ := http.NewRequest("GET", "show-received-websocket-frames?name=TradingApi", nil)
+
+	mgr.showReceivedWebSocketFrames(w, request)
+
+	w.AssertCalled(t, "Write", []byte(`{"tradingFeed":[{"Time":"2026-01-02T03:04:05Z","Data":"ping"}]}`))
+	w.AssertNotCalled(t, "WriteHeader", mock.Anything)
+}
+
+func TestShowReceivedWebSocketFrames_WritesStatusNotFound_WhenAPINotRegistered(t *testing.T) {
+	mgr := Manager{
+		apis: map[string]api.IAPI{},
+		log:  log.GetFakeLogger(),
+	}
+	w := new(fake.ResponseWriter)
+	w.On("WriteHeader", mock.AnythingOfType("int")).Return(1)
+	w.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
+	request, _ := http.NewRequest("GET", "show-received-websocket-frames?name=tradingApi", nil)
+
+	mgr.showReceivedWebSocketFrames(w, request)
+
+	w.AssertCalled(t, "WriteHeader", http.StatusNotFound)
+}
+
+func TestShowRegisteredMockAPI_WritesStatusNotFound_WhenAPINotRegistered(t *testing.T) {
+	mgr := Manager{
+		apis: map[string]api.IAPI{},
+		log:  log.GetFakeLogger(),
+	}
+	w := new(fake.ResponseWriter)
+	w.On("WriteHeader", mock.AnythingOfType("int")).Return(1)
+	w.On("Write", mock.AnythingOfType("[]uint8")).Return(1, nil)
+	request, _ := http.NewRequest("GET", "show-registered-mock-api?name=unknownApi", nil)
+
+	mgr.showRegisteredMockAPI(w, request)
+
+	w.AssertCalled(t, "WriteHeader", http.StatusNotFound)
+}
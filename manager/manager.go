@@ -4,7 +4,10 @@ package manager
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -22,6 +25,11 @@ import (
 // Manager coordinates and controls the mock APIs
 type Manager struct {
 	apis           map[string]api.IAPI
+	apiConfigs     map[string]config.APIConfig
+	apiDirs        []string
+	apiPaths       map[string]string
+	dataDir        string
+	sharedServers  map[int]api.ISharedServer
 	config         *config.AppConfig
 	server         wrapper.IServerOps
 	hubAPIHandlers map[string]map[string]func(http.ResponseWriter, *http.Request)
@@ -30,8 +38,51 @@ type Manager struct {
 	configManager  config.IManager
 }
 
-// NewManager returns an instance of the Manager type.
+// NewManager returns an instance of the Manager type that loads the mock APIs in the directories
+// configured, or in the default mock API directory if none are configured.
 func NewManager(appConfig *config.AppConfig) (*Manager, error) {
+	apiDirs := appConfig.APIDirs
+	if len(apiDirs) == 0 {
+		apiDirs = []string{constants.APIDir}
+	}
+	return NewManagerForDirs(appConfig, apiDirs)
+}
+
+// NewManagerForDir returns an instance of the Manager type that loads the mock APIs in the
+// directory provided.
+func NewManagerForDir(appConfig *config.AppConfig, apiDir string) (*Manager, error) {
+	return NewManagerForDirs(appConfig, []string{apiDir})
+}
+
+// NewManagerForDirs returns an instance of the Manager type that loads the mock APIs in the
+// directories provided. If more than one directory contains a mock API of the same name, the
+// mock API in the directory provided first is loaded.
+func NewManagerForDirs(appConfig *config.AppConfig, apiDirs []string) (*Manager, error) {
+	mgr, err := newManager(appConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr.apiDirs = apiDirs
+	mgr.configManager = config.NewConfigManagerWithDefaults(&appConfig.Defaults)
+	return mgr, nil
+}
+
+// NewManagerFromConfigs returns an instance of the Manager type that loads the mock APIs provided,
+// keyed by name, rather than reading them from a directory. Files named by the mock APIs'
+// endpoints are read from dataDir.
+func NewManagerFromConfigs(appConfig *config.AppConfig, apiConfigs map[string]config.APIConfig, dataDir string) (*Manager, error) {
+	mgr, err := newManager(appConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr.apiConfigs = apiConfigs
+	mgr.dataDir = dataDir
+	return mgr, nil
+}
+
+func newManager(appConfig *config.AppConfig) (*Manager, error) {
 	mgr := &Manager{}
 	mgr.log = log.NewLogger(&appConfig.Log, "manager").WithFields(logrus.Fields{
 		log.PortField:     appConfig.HTTP.Port,
@@ -42,7 +93,7 @@ func NewManager(appConfig *config.AppConfig) (*Manager, error) {
 	contextLogger := mgr.log.WithField(log.FuncField, ref.GetFuncName())
 	contextLogger.Info("creating new manager")
 
-	server, err := createManagerServer(appConfig.HTTP.Port, mgr)
+	server, err := createManagerServer(int(appConfig.HTTP.Port), mgr)
 	if err != nil {
 		contextLogger.WithError(err).Error("error creating manager")
 		return nil, err
@@ -51,25 +102,28 @@ func NewManager(appConfig *config.AppConfig) (*Manager, error) {
 	mgr.config = appConfig
 	mgr.server = wrapper.NewServerOps(server)
 	mgr.apis = make(map[string]api.IAPI)
+	mgr.apiPaths = make(map[string]string)
+	mgr.sharedServers = make(map[int]api.ISharedServer)
 	mgr.file = &wrapper.FileOps{}
-	mgr.configManager = config.NewConfigManager()
 	contextLogger.Info("successfully created new manager")
 	return mgr, nil
 }
 
-// StartMockAPIHub registers the mock apis and serves them.
+// StartMockAPIHub registers the mock apis and serves them, returning when the hub server stops.
 func (mgr *Manager) StartMockAPIHub() error {
 	contextLogger := mgr.log.WithField(log.FuncField, ref.GetFuncName())
 	contextLogger.Debug("starting mock API hub")
 
-	if err := mgr.loadMockAPIs(); err != nil {
-		contextLogger.WithError(err).Error("error loading mock APIs")
+	if err := mgr.startMockAPIsAndRegisterHubAPI(); err != nil {
 		return err
 	}
-	mgr.startMockAPIs()
-	contextLogger.Debug("successfully started mock APIs; will next start the mock API hub")
 
-	mgr.registerHubAPIHandlers()
+	if mgr.config.HTTP.Port == 0 {
+		if err := mgr.listenHubServer(); err != nil {
+			return err
+		}
+	}
+
 	if err := mgr.startHubServer(); err != nil {
 		contextLogger.WithError(err).Error("error starting hub server")
 		return err
@@ -79,15 +133,37 @@ func (mgr *Manager) StartMockAPIHub() error {
 	return nil
 }
 
+// StartMockAPIHubInBackground registers the mock apis and serves them, returning once the hub
+// server is listening. Errors the hub server encounters afterward are logged.
+func (mgr *Manager) StartMockAPIHubInBackground() error {
+	contextLogger := mgr.log.WithField(log.FuncField, ref.GetFuncName())
+	contextLogger.Debug("starting mock API hub in background")
+
+	if err := mgr.startMockAPIsAndRegisterHubAPI(); err != nil {
+		return err
+	}
+
+	if err := mgr.listenHubServer(); err != nil {
+		return err
+	}
+
+	go func() {
+		if err := mgr.startHubServer(); err != nil {
+			contextLogger.WithError(err).Error("error starting hub server")
+		}
+	}()
+	contextLogger.Debug("successfully started mock API hub in background")
+
+	return nil
+}
+
 // StopMockAPIHub shuts down all mock API servers and the hub server,
 // and panics on error.
 func (mgr *Manager) StopMockAPIHub() {
 	contextLogger := mgr.log.WithField(log.FuncField, ref.GetFuncName())
 	contextLogger.Debug("stopping mock API hub")
 
-	mgr.shutDownMockAPIs()
-
-	if err := mgr.shutdownHubServer(); err != nil {
+	if err := mgr.Shutdown(); err != nil {
 		contextLogger.WithError(err).Panic("error shutting down hub server")
 		return
 	}
@@ -95,9 +171,56 @@ func (mgr *Manager) StopMockAPIHub() {
 	contextLogger.Debug("successfully stopped mock API hub")
 }
 
+// Shutdown shuts down all mock API servers and the hub server, returning the error
+// encountered shutting down the hub server, if any.
+func (mgr *Manager) Shutdown() error {
+	mgr.shutDownMockAPIs()
+	return mgr.shutdownHubServer()
+}
+
+// GetHubPort returns the port on which the hub server listens.
+func (mgr *Manager) GetHubPort() int {
+	return int(mgr.config.HTTP.Port)
+}
+
+// GetAPI returns the mock API registered under the name provided.
+func (mgr *Manager) GetAPI(name string) (api.IAPI, bool) {
+	api, exists := mgr.apis[name]
+	return api, exists
+}
+
+func (mgr *Manager) startMockAPIsAndRegisterHubAPI() error {
+	contextLogger := mgr.log.WithField(log.FuncField, ref.GetFuncName())
+
+	if err := mgr.loadMockAPIs(); err != nil {
+		contextLogger.WithError(err).Error("error loading mock APIs")
+		return err
+	}
+	mgr.startMockAPIs()
+	mgr.writePortsFile()
+	contextLogger.Debug("successfully started mock APIs; will next start the mock API hub")
+
+	mgr.registerHubAPIHandlers()
+	return nil
+}
+
+func (mgr *Manager) listenHubServer() error {
+	contextLogger := mgr.log.WithField(log.FuncField, ref.GetFuncName())
+
+	port, err := mgr.server.Listen()
+	if err != nil {
+		contextLogger.WithError(err).Error("error listening for hub server")
+		return err
+	}
+
+	mgr.config.HTTP.Port = config.Port(port)
+	contextLogger.WithField(log.PortField, port).Info("hub server listening")
+	return nil
+}
+
 func (mgr *Manager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	method := strings.ToUpper(r.Method)
-	path := str.CleanURL(r.URL.String())
+	path := str.CleanURL(r.URL.Path)
 	contextLogger := mgr.log.WithFields(logrus.Fields{
 		log.MethodField: method,
 		log.PathField:   path,
@@ -140,6 +263,16 @@ func (mgr *Manager) shutDownMockAPIs() {
 		contextLoggerAPI.Info("successfully shut down mock API")
 	}
 
+	for port, sharedServer := range mgr.sharedServers {
+		contextLoggerShared := contextLogger.WithField(log.PortField, port)
+		contextLoggerShared.Info("shutting down shared server")
+		if err := sharedServer.Shutdown(); err != nil {
+			contextLoggerShared.WithError(err).Error("error shutting down shared server")
+			continue
+		}
+		contextLoggerShared.Info("successfully shut down shared server")
+	}
+
 	contextLogger.Debug("finished shutting down mock APIs")
 }
 
@@ -216,20 +349,43 @@ func (mgr *Manager) startMockAPIs() {
 			log.PortField:    api.GetPort(),
 		})
 		contextLoggerAPI.Debug("starting mock API")
-		if err := api.Start(dir, mgr.config.HTTP.CertFile, mgr.config.HTTP.KeyFile); err != nil {
+		if err := api.Start(mgr.apiPaths[dir], mgr.config.HTTP.CertFile, mgr.config.HTTP.KeyFile); err != nil {
 			contextLoggerAPI.WithError(err).Error("error starting mock API -- moving on to next mock API")
 		}
 	}
+
+	for port, sharedServer := range mgr.sharedServers {
+		contextLoggerShared := contextLogger.WithField(log.PortField, port)
+		contextLoggerShared.Debug("starting shared server")
+		if err := sharedServer.Start(mgr.config.HTTP.CertFile, mgr.config.HTTP.KeyFile); err != nil {
+			contextLoggerShared.WithError(err).Error("error starting shared server -- moving on to next shared server")
+		}
+	}
 }
 
 func (mgr *Manager) loadMockAPIs() error {
+	mgr.apiPaths = make(map[string]string)
+	if mgr.apiConfigs != nil {
+		mgr.loadMockAPIsFromConfigs()
+		return nil
+	}
+
+	for _, apiDir := range mgr.apiDirs {
+		if err := mgr.loadMockAPIsFromDir(apiDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (mgr *Manager) loadMockAPIsFromDir(apiDir string) error {
 	contextLogger := mgr.log.WithFields(logrus.Fields{
-		log.FuncField: ref.GetFuncName(),
-		"apiDir":      constants.APIDir,
+		log.FuncField:   ref.GetFuncName(),
+		log.APIDirField: apiDir,
 	})
 	contextLogger.Debug("loading mock APIs")
 
-	files, err := mgr.file.ReadDir(constants.APIDir)
+	files, err := mgr.file.ReadDir(apiDir)
 	if err != nil {
 		contextLogger.WithError(err).Error("error reading API directory")
 		return err
@@ -238,38 +394,112 @@ func (mgr *Manager) loadMockAPIs() error {
 	for _, file := range files {
 		contextLoggerFile := contextLogger.WithField("file", file.Name())
 
-		apiConfig, err := mgr.configManager.GetAPIConfig(file)
+		// Mock APIs are named by their own directories, wherever those are in the tree, so a mock API
+		// in a grouping directory can have the name of one loaded before it.
+		if loadedPath, exists := mgr.apiPaths[file.Name()]; exists {
+			contextLoggerFile.WithField("loadedFrom", loadedPath).Error("mock API name collision: a mock API of this name is already loaded -- moving on to next mock API file")
+			continue
+		}
+
+		apiConfig, err := mgr.configManager.GetAPIConfig(apiDir, file)
+		if err == config.ErrNotAPIDir {
+			if !isGroupDir(file) {
+				continue
+			}
+			if err := mgr.loadMockAPIsFromDir(fmt.Sprintf("%s/%s", apiDir, file.Name())); err != nil {
+				return err
+			}
+			continue
+		}
 		if err != nil {
 			contextLoggerFile.WithError(err).Error("error getting API config from file -- moving on to next mock API file")
 			continue
 		}
 
-		contextLoggerFileAPI := contextLoggerFile.WithFields(logrus.Fields{
-			log.BaseURLField:  apiConfig.BaseURL,
-			log.UseTLSField:   apiConfig.HTTP.UseTLS,
-			log.CertFileField: apiConfig.HTTP.CertFile,
-			log.KeyFileField:  apiConfig.HTTP.KeyFile,
-			log.PortField:     apiConfig.HTTP.Port,
-		})
+		mgr.loadMockAPI(file.Name(), fmt.Sprintf("%s/%s", apiDir, file.Name()), apiConfig, contextLoggerFile)
+	}
+	contextLogger.Debug("finished loading mock APIs")
+	return nil
+}
 
-		if mgr.apiByPortExists(apiConfig.HTTP.Port) {
-			contextLoggerFileAPI.Warn("a mock API is already loaded on this port -- moving on to next mock API")
-			continue
+// isGroupDir reports whether a directory that is not a mock API directory groups mock API directories,
+// in which case mock APIs are loaded from it too. Hidden directories are not searched.
+func isGroupDir(file os.FileInfo) bool {
+	return file.IsDir() && !strings.HasPrefix(file.Name(), ".")
+}
+
+func (mgr *Manager) loadMockAPIsFromConfigs() {
+	contextLogger := mgr.log.WithField(log.FuncField, ref.GetFuncName())
+	contextLogger.Debug("loading mock APIs from configurations provided")
+
+	names := make([]string, 0, len(mgr.apiConfigs))
+	for name := range mgr.apiConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		apiConfig := mgr.apiConfigs[name]
+		mgr.loadMockAPI(name, mgr.dataDir, &apiConfig, contextLogger.WithField("apiName", name))
+	}
+	contextLogger.Debug("finished loading mock APIs")
+}
+
+// loadMockAPI loads a mock API under the name provided. The mock API reads the files its endpoints
+// name from path.
+func (mgr *Manager) loadMockAPI(name, path string, apiConfig *config.APIConfig, logger *logrus.Entry) {
+	contextLogger := logger.WithFields(logrus.Fields{
+		log.BaseURLField:  apiConfig.BaseURL,
+		log.UseTLSField:   apiConfig.HTTP.UseTLS,
+		log.CertFileField: apiConfig.HTTP.CertFile,
+		log.KeyFileField:  apiConfig.HTTP.KeyFile,
+		log.PortField:     apiConfig.HTTP.Port,
+	})
+
+	port := int(apiConfig.HTTP.Port)
+	_, sharedServerExists := mgr.sharedServers[port]
+	if port != 0 && mgr.apiByPortExists(port) && !(apiConfig.HTTP.Shared && sharedServerExists) {
+		contextLogger.Warn("a mock API is already loaded on this port -- moving on to next mock API")
+		return
+	}
+
+	if mgr.config != nil && len(mgr.config.APILogLevel) > 0 {
+		apiConfig.Log.Level = mgr.config.APILogLevel
+	}
+
+	api, err := api.NewAPI(apiConfig)
+	if err != nil {
+		contextLogger.WithError(err).Error("error loading mock API -- moving on to next mock API")
+		return
+	}
+
+	if apiConfig.HTTP.Shared {
+		if err := mgr.addToSharedServer(api, apiConfig.HTTP); err != nil {
+			contextLogger.WithError(err).Error("error adding mock API to shared server -- moving on to next mock API")
+			return
 		}
+	}
+
+	contextLogger.Info("successfully loaded mock API")
+	mgr.apis[name] = api
+	mgr.apiPaths[name] = path
+}
 
-		api, err := api.NewAPI(apiConfig)
+func (mgr *Manager) addToSharedServer(mockAPI *api.API, httpConfig config.HTTP) error {
+	sharedServer, exists := mgr.sharedServers[int(httpConfig.Port)]
+	if !exists {
+		newSharedServer, err := api.NewSharedServer(httpConfig, mgr.log)
 		if err != nil {
-			contextLoggerFileAPI.WithError(err).Error("error loading mock API -- moving on to next mock API")
-			continue
+			return err
 		}
+		sharedServer = newSharedServer
+	}
 
-		if api != nil {
-			contextLoggerFileAPI.Info("successfully loaded mock API")
-			mgr.apis[file.Name()] = api
-			continue
-		}
+	if err := sharedServer.Add(mockAPI); err != nil {
+		return err
 	}
-	contextLogger.Debug("finished loading mock APIs")
+
+	mgr.sharedServers[int(httpConfig.Port)] = sharedServer
 	return nil
 }
 
@@ -283,8 +513,8 @@ func (mgr *Manager) apiByPortExists(port int) bool {
 }
 
 func createManagerServer(port int, mgr *Manager) (*http.Server, error) {
-	if port == 0 {
-		return nil, errors.New("no port provided")
+	if port < 0 {
+		return nil, errors.New("invalid port provided")
 	}
 
 	server := &http.Server{
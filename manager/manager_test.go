@@ -3,10 +3,12 @@ package manager
 import (
 	"errors"
 	"net/http"
+	"os"
 	"testing"
 
 	"github.com/wcsanders1/MockApiHub/api"
 	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/constants"
 	"github.com/wcsanders1/MockApiHub/fake"
 	"github.com/wcsanders1/MockApiHub/helper"
 	"github.com/wcsanders1/MockApiHub/log"
@@ -32,10 +34,10 @@ func TestNewManager_ReturnsManager_WhenConfigValid(t *testing.T) {
 	assert.IsType(&Manager{}, result)
 }
 
-func TestNewManager_ReturnsError_WhenPortNotProvided(t *testing.T) {
+func TestNewManager_ReturnsError_WhenPortIsNegative(t *testing.T) {
 	cfg := &config.AppConfig{
 		HTTP: config.HTTP{
-			Port: 0,
+			Port: -1,
 		},
 	}
 
@@ -46,6 +48,36 @@ func TestNewManager_ReturnsError_WhenPortNotProvided(t *testing.T) {
 	assert.Error(err)
 }
 
+func TestNewManagerForDir_UsesDirectory_WhenCalled(t *testing.T) {
+	result, err := NewManagerForDir(&config.AppConfig{}, "testdata/mockApis")
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal([]string{"testdata/mockApis"}, result.apiDirs)
+	assert.Nil(result.apiConfigs)
+}
+
+func TestLoadMockAPIs_LoadsConfigsProvided_WhenCreatedFromConfigs(t *testing.T) {
+	apiConfigs := map[string]config.APIConfig{
+		"customers": *helper.GetFakeAPIConfig(4000),
+		"students":  *helper.GetFakeAPIConfig(4001),
+		"duplicate": *helper.GetFakeAPIConfig(4001),
+	}
+	mgr, _ := NewManagerFromConfigs(&config.AppConfig{}, apiConfigs, "testdata")
+	fileOps := new(wrapper.FakeFileOps)
+	mgr.file = fileOps
+
+	err := mgr.loadMockAPIs()
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal(2, len(mgr.apis))
+	assert.Contains(mgr.apis, "customers")
+	assert.Contains(mgr.apis, "duplicate")
+	assert.Equal("testdata", mgr.apiPaths["customers"])
+	fileOps.AssertNotCalled(t, "ReadDir", mock.Anything)
+}
+
 func TestAPIByPortExists_ReturnsFalse_WhenProvidedUnregisteredPort(t *testing.T) {
 	mgr := Manager{
 		apis: make(map[string]api.IAPI),
@@ -97,10 +129,11 @@ func TestLoadMockAPIs_ReturnsNil_WhenProvidedValidAPI(t *testing.T) {
 	fileOps.On("ReadDir", mock.AnythingOfType("string")).Return(fileCollection, nil)
 	apiConfig := helper.GetFakeAPIConfig(4000)
 	configManager := new(config.FakeManager)
-	configManager.On("GetAPIConfig", mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
+	configManager.On("GetAPIConfig", mock.AnythingOfType("string"), mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
 	mgr := Manager{
 		file:          fileOps,
 		configManager: configManager,
+		apiDirs:       []string{constants.APIDir},
 		log:           log.GetFakeLogger(),
 		apis:          make(map[string]api.IAPI),
 	}
@@ -109,7 +142,125 @@ func TestLoadMockAPIs_ReturnsNil_WhenProvidedValidAPI(t *testing.T) {
 
 	assert := assert.New(t)
 	assert.Nil(err)
-	configManager.AssertCalled(t, "GetAPIConfig", mock.AnythingOfType("*fake.FileInfo"))
+	configManager.AssertCalled(t, "GetAPIConfig", mock.AnythingOfType("string"), mock.AnythingOfType("*fake.FileInfo"))
+}
+
+func TestLoadMockAPIs_LoadsFirstAPI_WhenDirectoriesHaveAPIsOfSameName(t *testing.T) {
+	_, baseCollection := helper.GetFakeFileInfoAndCollection("", "customersApi")
+	_, overrideCollection := helper.GetFakeFileInfoAndCollection("", "customersApi")
+	_, studentsCollection := helper.GetFakeFileInfoAndCollection("", "studentsApi")
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("ReadDir", "fixtures/a").Return(baseCollection, nil)
+	fileOps.On("ReadDir", "fixtures/b").Return(append(overrideCollection, studentsCollection...), nil)
+	configManager := new(config.FakeManager)
+	configManager.On("GetAPIConfig", "fixtures/a", baseCollection[0]).Return(helper.GetFakeAPIConfig(4000), nil)
+	configManager.On("GetAPIConfig", "fixtures/b", studentsCollection[0]).Return(helper.GetFakeAPIConfig(4001), nil)
+	mgr := Manager{
+		file:          fileOps,
+		configManager: configManager,
+		apiDirs:       []string{"fixtures/a", "fixtures/b"},
+		log:           log.GetFakeLogger(),
+		apis:          make(map[string]api.IAPI),
+	}
+
+	err := mgr.loadMockAPIs()
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal(map[string]string{
+		"customersApi": "fixtures/a/customersApi",
+		"studentsApi":  "fixtures/b/studentsApi",
+	}, mgr.apiPaths)
+	configManager.AssertNotCalled(t, "GetAPIConfig", "fixtures/b", overrideCollection[0])
+}
+
+func TestLoadMockAPIs_LoadsNestedAPIs_WhenDirectoryGroupsAPIs(t *testing.T) {
+	groupDir := new(fake.FileInfo)
+	groupDir.On("Name").Return("billing")
+	groupDir.On("IsDir").Return(true)
+	hiddenDir := new(fake.FileInfo)
+	hiddenDir.On("Name").Return(".git")
+	hiddenDir.On("IsDir").Return(true)
+	_, invoicesCollection := helper.GetFakeFileInfoAndCollection("", "invoicesApi")
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("ReadDir", "fixtures").Return([]os.FileInfo{groupDir, hiddenDir}, nil)
+	fileOps.On("ReadDir", "fixtures/billing").Return(invoicesCollection, nil)
+	configManager := new(config.FakeManager)
+	configManager.On("GetAPIConfig", "fixtures", groupDir).Return((*config.APIConfig)(nil), config.ErrNotAPIDir)
+	configManager.On("GetAPIConfig", "fixtures", hiddenDir).Return((*config.APIConfig)(nil), config.ErrNotAPIDir)
+	configManager.On("GetAPIConfig", "fixtures/billing", invoicesCollection[0]).Return(helper.GetFakeAPIConfig(4000), nil)
+	mgr := Manager{
+		file:          fileOps,
+		configManager: configManager,
+		apiDirs:       []string{"fixtures"},
+		log:           log.GetFakeLogger(),
+		apis:          make(map[string]api.IAPI),
+	}
+
+	err := mgr.loadMockAPIs()
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal(map[string]string{"invoicesApi": "fixtures/billing/invoicesApi"}, mgr.apiPaths)
+	fileOps.AssertNotCalled(t, "ReadDir", "fixtures/.git")
+}
+
+func TestLoadMockAPIs_LoadsFirstAPI_WhenGroupedAPIHasSameName(t *testing.T) {
+	groupDir := new(fake.FileInfo)
+	groupDir.On("Name").Return("billing")
+	groupDir.On("IsDir").Return(true)
+	_, topCollection := helper.GetFakeFileInfoAndCollection("", "invoicesApi")
+	_, groupedCollection := helper.GetFakeFileInfoAndCollection("", "invoicesApi")
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("ReadDir", "fixtures").Return(append(topCollection, groupDir), nil)
+	fileOps.On("ReadDir", "fixtures/billing").Return(groupedCollection, nil)
+	configManager := new(config.FakeManager)
+	configManager.On("GetAPIConfig", "fixtures", topCollection[0]).Return(helper.GetFakeAPIConfig(4000), nil)
+	configManager.On("GetAPIConfig", "fixtures", groupDir).Return((*config.APIConfig)(nil), config.ErrNotAPIDir)
+	mgr := Manager{
+		file:          fileOps,
+		configManager: configManager,
+		apiDirs:       []string{"fixtures"},
+		log:           log.GetFakeLogger(),
+		apis:          make(map[string]api.IAPI),
+	}
+
+	err := mgr.loadMockAPIs()
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal(map[string]string{"invoicesApi": "fixtures/invoicesApi"}, mgr.apiPaths)
+	configManager.AssertNotCalled(t, "GetAPIConfig", "fixtures/billing", groupedCollection[0])
+}
+
+func TestLoadMockAPIs_OverridesAPILogLevel_WhenConfigured(t *testing.T) {
+	_, fileCollection := helper.GetFakeFileInfoAndCollection("", "customersApi")
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("ReadDir", mock.AnythingOfType("string")).Return(fileCollection, nil)
+	apiConfig := helper.GetFakeAPIConfig(4000)
+	apiConfig.Log.Level = "debug"
+	configManager := new(config.FakeManager)
+	configManager.On("GetAPIConfig", mock.AnythingOfType("string"), mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
+	mgr := Manager{
+		file:          fileOps,
+		configManager: configManager,
+		apiDirs:       []string{constants.APIDir},
+		config:        &config.AppConfig{APILogLevel: "warn"},
+		log:           log.GetFakeLogger(),
+		apis:          make(map[string]api.IAPI),
+	}
+
+	mgr.loadMockAPIs()
+
+	assert.Equal(t, "warn", apiConfig.Log.Level)
+}
+
+func TestNewManager_UsesConfiguredDirectories_WhenProvided(t *testing.T) {
+	result, err := NewManager(&config.AppConfig{APIDirs: []string{"fixtures/a", "fixtures/b"}})
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal([]string{"fixtures/a", "fixtures/b"}, result.apiDirs)
 }
 
 func TestLoadMockAPIs_ReturnsNil_WhenGetConfigFails(t *testing.T) {
@@ -118,10 +269,11 @@ func TestLoadMockAPIs_ReturnsNil_WhenGetConfigFails(t *testing.T) {
 	fileOps.On("ReadDir", mock.AnythingOfType("string")).Return(fileCollection, nil)
 	apiConfig := helper.GetFakeAPIConfig(4000)
 	configManager := new(config.FakeManager)
-	configManager.On("GetAPIConfig", mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, errors.New(""))
+	configManager.On("GetAPIConfig", mock.AnythingOfType("string"), mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, errors.New(""))
 	mgr := Manager{
 		file:          fileOps,
 		configManager: configManager,
+		apiDirs:       []string{constants.APIDir},
 		log:           log.GetFakeLogger(),
 		apis:          make(map[string]api.IAPI),
 	}
@@ -130,7 +282,31 @@ func TestLoadMockAPIs_ReturnsNil_WhenGetConfigFails(t *testing.T) {
 
 	assert := assert.New(t)
 	assert.Nil(err)
-	configManager.AssertCalled(t, "GetAPIConfig", mock.AnythingOfType("*fake.FileInfo"))
+	configManager.AssertCalled(t, "GetAPIConfig", mock.AnythingOfType("string"), mock.AnythingOfType("*fake.FileInfo"))
+}
+
+func TestLoadMockAPIs_ReturnsError_WhenReadingGroupedDirectoryFails(t *testing.T) {
+	groupDir := new(fake.FileInfo)
+	groupDir.On("Name").Return("billing")
+	groupDir.On("IsDir").Return(true)
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("ReadDir", "fixtures").Return([]os.FileInfo{groupDir}, nil)
+	fileOps.On("ReadDir", "fixtures/billing").Return([]os.FileInfo(nil), errors.New("permission denied"))
+	configManager := new(config.FakeManager)
+	configManager.On("GetAPIConfig", "fixtures", groupDir).Return((*config.APIConfig)(nil), config.ErrNotAPIDir)
+	mgr := Manager{
+		file:          fileOps,
+		configManager: configManager,
+		apiDirs:       []string{"fixtures"},
+		log:           log.GetFakeLogger(),
+		apis:          make(map[string]api.IAPI),
+	}
+
+	err := mgr.loadMockAPIs()
+
+	assert := assert.New(t)
+	assert.Error(err)
+	assert.Contains(err.Error(), "permission denied")
 }
 
 func TestLoadMockAPIs_ReturnsError_WhenReadDirFails(t *testing.T) {
@@ -141,6 +317,7 @@ func TestLoadMockAPIs_ReturnsError_WhenReadDirFails(t *testing.T) {
 	mgr := Manager{
 		file:          fileOps,
 		configManager: configManager,
+		apiDirs:       []string{constants.APIDir},
 		log:           log.GetFakeLogger(),
 		apis:          make(map[string]api.IAPI),
 	}
@@ -149,7 +326,7 @@ func TestLoadMockAPIs_ReturnsError_WhenReadDirFails(t *testing.T) {
 
 	assert := assert.New(t)
 	assert.Error(err)
-	configManager.AssertNotCalled(t, "GetAPIConfig", mock.Anything)
+	configManager.AssertNotCalled(t, "GetAPIConfig", mock.Anything, mock.Anything)
 }
 
 func TestLoadMockAPIs_LoadsOneAPI_WhenProvidedTwoWithSamePort(t *testing.T) {
@@ -160,12 +337,70 @@ func TestLoadMockAPIs_LoadsOneAPI_WhenProvidedTwoWithSamePort(t *testing.T) {
 	fileOps.On("ReadDir", mock.AnythingOfType("string")).Return(fileCollection, nil)
 	apiConfig := helper.GetFakeAPIConfig(4000)
 	configManager := new(config.FakeManager)
-	configManager.On("GetAPIConfig", mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
+	configManager.On("GetAPIConfig", mock.AnythingOfType("string"), mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
+	mgr := Manager{
+		file:          fileOps,
+		configManager: configManager,
+		apiDirs:       []string{constants.APIDir},
+		log:           log.GetFakeLogger(),
+		apis:          make(map[string]api.IAPI),
+	}
+
+	err := mgr.loadMockAPIs()
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal(1, len(mgr.apis))
+	configManager.AssertCalled(t, "GetAPIConfig", mock.AnythingOfType("string"), mock.AnythingOfType("*fake.FileInfo"))
+}
+
+func TestLoadMockAPIs_LoadsBothAPIs_WhenProvidedTwoSharedAPIsWithSamePort(t *testing.T) {
+	_, fileCollection := helper.GetFakeFileInfoAndCollection("", "testconfig.toml")
+	fileInfo, _ := helper.GetFakeFileInfoAndCollection("", "testconfig2.toml")
+	fileCollection = append(fileCollection, fileInfo)
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("ReadDir", mock.AnythingOfType("string")).Return(fileCollection, nil)
+	apiConfig := helper.GetFakeAPIConfig(4000)
+	apiConfig.HTTP.Shared = true
+	configManager := new(config.FakeManager)
+	configManager.On("GetAPIConfig", mock.AnythingOfType("string"), mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
+	mgr := Manager{
+		file:          fileOps,
+		configManager: configManager,
+		apiDirs:       []string{constants.APIDir},
+		log:           log.GetFakeLogger(),
+		apis:          make(map[string]api.IAPI),
+		sharedServers: make(map[int]api.ISharedServer),
+	}
+
+	err := mgr.loadMockAPIs()
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal(2, len(mgr.apis))
+	assert.Equal(1, len(mgr.sharedServers))
+	assert.Contains(mgr.sharedServers, 4000)
+}
+
+func TestLoadMockAPIs_DoesNotLoadSharedAPI_WhenUnsharedAPIHasSamePort(t *testing.T) {
+	_, fileCollection := helper.GetFakeFileInfoAndCollection("", "testconfig.toml")
+	fileInfo, _ := helper.GetFakeFileInfoAndCollection("", "testconfig2.toml")
+	fileCollection = append(fileCollection, fileInfo)
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("ReadDir", mock.AnythingOfType("string")).Return(fileCollection, nil)
+	unsharedConfig := helper.GetFakeAPIConfig(4000)
+	sharedConfig := helper.GetFakeAPIConfig(4000)
+	sharedConfig.HTTP.Shared = true
+	configManager := new(config.FakeManager)
+	configManager.On("GetAPIConfig", mock.AnythingOfType("string"), fileCollection[0]).Return(unsharedConfig, nil)
+	configManager.On("GetAPIConfig", mock.AnythingOfType("string"), fileCollection[1]).Return(sharedConfig, nil)
 	mgr := Manager{
 		file:          fileOps,
 		configManager: configManager,
+		apiDirs:       []string{constants.APIDir},
 		log:           log.GetFakeLogger(),
 		apis:          make(map[string]api.IAPI),
+		sharedServers: make(map[int]api.ISharedServer),
 	}
 
 	err := mgr.loadMockAPIs()
@@ -173,19 +408,20 @@ func TestLoadMockAPIs_LoadsOneAPI_WhenProvidedTwoWithSamePort(t *testing.T) {
 	assert := assert.New(t)
 	assert.Nil(err)
 	assert.Equal(1, len(mgr.apis))
-	configManager.AssertCalled(t, "GetAPIConfig", mock.AnythingOfType("*fake.FileInfo"))
+	assert.Empty(mgr.sharedServers)
 }
 
-func TestLoadMockAPIs_DoesNotLoadAPI_WhenPortIsZero(t *testing.T) {
+func TestLoadMockAPIs_LoadsAPI_WhenPortIsZero(t *testing.T) {
 	_, fileCollection := helper.GetFakeFileInfoAndCollection("", "testconfig.toml")
 	fileOps := new(wrapper.FakeFileOps)
 	fileOps.On("ReadDir", mock.AnythingOfType("string")).Return(fileCollection, nil)
 	apiConfig := helper.GetFakeAPIConfig(0)
 	configManager := new(config.FakeManager)
-	configManager.On("GetAPIConfig", mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
+	configManager.On("GetAPIConfig", mock.AnythingOfType("string"), mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
 	mgr := Manager{
 		file:          fileOps,
 		configManager: configManager,
+		apiDirs:       []string{constants.APIDir},
 		log:           log.GetFakeLogger(),
 		apis:          make(map[string]api.IAPI),
 	}
@@ -194,7 +430,7 @@ func TestLoadMockAPIs_DoesNotLoadAPI_WhenPortIsZero(t *testing.T) {
 
 	assert := assert.New(t)
 	assert.Nil(err)
-	assert.Empty(mgr.apis)
+	assert.Equal(1, len(mgr.apis))
 }
 
 func TestStartMockAPIs_StartsAPI_WithCertAndKey(t *testing.T) {
@@ -209,14 +445,15 @@ func TestStartMockAPIs_StartsAPI_WithCertAndKey(t *testing.T) {
 		dir: fakeAPI,
 	}
 	mgr := Manager{
-		apis:   apis,
-		config: helper.GetFakeAppConfig(certFile, keyFile),
-		log:    log.GetFakeLogger(),
+		apis:     apis,
+		apiPaths: map[string]string{dir: constants.APIDir + "/" + dir},
+		config:   helper.GetFakeAppConfig(certFile, keyFile),
+		log:      log.GetFakeLogger(),
 	}
 
 	mgr.startMockAPIs()
 
-	fakeAPI.AssertCalled(t, "Start", dir, certFile, keyFile)
+	fakeAPI.AssertCalled(t, "Start", constants.APIDir+"/"+dir, certFile, keyFile)
 }
 
 func TestStartMockAPIs_DoesNotPanic_WhenStartFails(t *testing.T) {
@@ -231,13 +468,14 @@ func TestStartMockAPIs_DoesNotPanic_WhenStartFails(t *testing.T) {
 		dir: fakeAPI,
 	}
 	mgr := Manager{
-		apis:   apis,
-		config: helper.GetFakeAppConfig(certFile, keyFile),
-		log:    log.GetFakeLogger(),
+		apis:     apis,
+		apiPaths: map[string]string{dir: constants.APIDir + "/" + dir},
+		config:   helper.GetFakeAppConfig(certFile, keyFile),
+		log:      log.GetFakeLogger(),
 	}
 
 	assert.NotPanics(t, func() { mgr.startMockAPIs() })
-	fakeAPI.AssertCalled(t, "Start", dir, certFile, keyFile)
+	fakeAPI.AssertCalled(t, "Start", constants.APIDir+"/"+dir, certFile, keyFile)
 }
 
 func TestStartHubServerUsingTLS_ReturnsNil_WhenServerStarted(t *testing.T) {
@@ -519,6 +757,24 @@ func TestServeHTTP_FindsHandler_WhenHandlerExists(t *testing.T) {
 	responseWriter.AssertNotCalled(t, "WriteHeader", http.StatusNotFound)
 }
 
+func TestServeHTTP_FindsHandler_WhenURLHasQuery(t *testing.T) {
+	method := "GET"
+	request, _ := http.NewRequest(method, "/test/path?name=testApi", nil)
+	responseWriter := new(fake.ResponseWriter)
+	handlerCalled := false
+	hubAPIHandlers := make(map[string]map[string]func(http.ResponseWriter, *http.Request))
+	hubAPIHandlers[method] = make(map[string]func(http.ResponseWriter, *http.Request))
+	hubAPIHandlers[method][str.CleanURL("/test/path")] = func(w http.ResponseWriter, r *http.Request) { handlerCalled = true }
+	mgr := Manager{
+		hubAPIHandlers: hubAPIHandlers,
+		log:            log.GetFakeLogger(),
+	}
+
+	mgr.ServeHTTP(responseWriter, request)
+
+	assert.True(t, handlerCalled)
+}
+
 func TestServeHTTP_SetsStatusNotFound_WhenNotProvidedPath(t *testing.T) {
 	request, _ := http.NewRequest("", "", nil)
 	responseWriter := new(fake.ResponseWriter)
@@ -604,7 +860,7 @@ func TestStartMockAPIHub_ReturnsNil_OnSuccess(t *testing.T) {
 	fileOps.On("ReadDir", mock.AnythingOfType("string")).Return(fileCollection, nil)
 	apiConfig := helper.GetFakeAPIConfig(4000)
 	configManager := new(config.FakeManager)
-	configManager.On("GetAPIConfig", mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
+	configManager.On("GetAPIConfig", mock.AnythingOfType("string"), mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
 	dir := "fakeAPI"
 	fakeAPI := new(api.FakeAPI)
 	fakeAPI.On("GetBaseURL").Return("baseURL")
@@ -618,6 +874,7 @@ func TestStartMockAPIHub_ReturnsNil_OnSuccess(t *testing.T) {
 	mgr := Manager{
 		file:          fileOps,
 		configManager: configManager,
+		apiDirs:       []string{constants.APIDir},
 		apis:          apis,
 		config:        helper.GetFakeAppConfig("", ""),
 		log:           log.GetFakeLogger(),
@@ -635,7 +892,7 @@ func TestStartMockAPIHub_ReturnsError_WhenStartMockAPIHubFails(t *testing.T) {
 	fileOps.On("ReadDir", mock.AnythingOfType("string")).Return(fileCollection, errors.New(""))
 	apiConfig := helper.GetFakeAPIConfig(4000)
 	configManager := new(config.FakeManager)
-	configManager.On("GetAPIConfig", mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
+	configManager.On("GetAPIConfig", mock.AnythingOfType("string"), mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
 	dir := "fakeAPI"
 	fakeAPI := new(api.FakeAPI)
 	fakeAPI.On("GetBaseURL").Return("baseURL")
@@ -649,6 +906,7 @@ func TestStartMockAPIHub_ReturnsError_WhenStartMockAPIHubFails(t *testing.T) {
 	mgr := Manager{
 		file:          fileOps,
 		configManager: configManager,
+		apiDirs:       []string{constants.APIDir},
 		apis:          apis,
 		config:        helper.GetFakeAppConfig("", ""),
 		log:           log.GetFakeLogger(),
@@ -666,7 +924,7 @@ func TestStartMockAPIHub_ReturnsError_WhenStartHubServerFails(t *testing.T) {
 	fileOps.On("ReadDir", mock.AnythingOfType("string")).Return(fileCollection, nil)
 	apiConfig := helper.GetFakeAPIConfig(4000)
 	configManager := new(config.FakeManager)
-	configManager.On("GetAPIConfig", mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
+	configManager.On("GetAPIConfig", mock.AnythingOfType("string"), mock.AnythingOfType("*fake.FileInfo")).Return(apiConfig, nil)
 	dir := "fakeAPI"
 	fakeAPI := new(api.FakeAPI)
 	fakeAPI.On("GetBaseURL").Return("baseURL")
@@ -680,6 +938,7 @@ func TestStartMockAPIHub_ReturnsError_WhenStartHubServerFails(t *testing.T) {
 	mgr := Manager{
 		file:          fileOps,
 		configManager: configManager,
+		apiDirs:       []string{constants.APIDir},
 		apis:          apis,
 		config:        helper.GetFakeAppConfig("", ""),
 		log:           log.GetFakeLogger(),
@@ -690,3 +949,57 @@ func TestStartMockAPIHub_ReturnsError_WhenStartHubServerFails(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestStartMockAPIHub_ListensOnFreePort_WhenPortIsZero(t *testing.T) {
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("ReadDir", mock.AnythingOfType("string")).Return([]os.FileInfo{}, nil)
+	fakeServer := wrapper.NewFakeServerOps()
+	fakeServer.On("Listen").Return(5005, nil)
+	fakeServer.On("ListenAndServe").Return(nil)
+	mgr := Manager{
+		file:   fileOps,
+		apis:   map[string]api.IAPI{},
+		config: &config.AppConfig{},
+		log:    log.GetFakeLogger(),
+		server: fakeServer,
+	}
+
+	err := mgr.StartMockAPIHub()
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal(5005, mgr.GetHubPort())
+}
+
+func TestStartMockAPIHubInBackground_ReturnsError_WhenListenFails(t *testing.T) {
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("ReadDir", mock.AnythingOfType("string")).Return([]os.FileInfo{}, nil)
+	fakeServer := wrapper.NewFakeServerOps()
+	fakeServer.On("Listen").Return(0, errors.New(""))
+	mgr := Manager{
+		file:   fileOps,
+		apis:   map[string]api.IAPI{},
+		config: helper.GetFakeAppConfig("", ""),
+		log:    log.GetFakeLogger(),
+		server: fakeServer,
+	}
+
+	err := mgr.StartMockAPIHubInBackground()
+
+	assert.Error(t, err)
+	fakeServer.AssertNotCalled(t, "ListenAndServe")
+}
+
+func TestShutdown_ReturnsError_WhenHubShutdownFails(t *testing.T) {
+	fakeServer := wrapper.NewFakeServerOps()
+	fakeServer.On("Shutdown", mock.AnythingOfType("*context.timerCtx")).Return(errors.New(""))
+	mgr := Manager{
+		apis:   map[string]api.IAPI{},
+		log:    log.GetFakeLogger(),
+		server: fakeServer,
+	}
+
+	err := mgr.Shutdown()
+
+	assert.Error(t, err)
+}
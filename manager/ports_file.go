@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/wcsanders1/MockApiHub/log"
+	"github.com/wcsanders1/MockApiHub/ref"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	portsFileJSONExt = ".json"
+	portsFilePerm    = 0644
+	portEnvVarSuffix = "_PORT"
+)
+
+// writePortsFile writes the port of each mock API to the ports file, if one is configured. A ports
+// file ending in .json receives a JSON object mapping mock API names to ports; any other ports file
+// receives one NAME_PORT=port line per mock API, suitable for sourcing in a shell.
+func (mgr *Manager) writePortsFile() {
+	if mgr.config == nil || len(mgr.config.PortsFile) == 0 {
+		return
+	}
+
+	contextLogger := mgr.log.WithFields(logrus.Fields{
+		log.FuncField: ref.GetFuncName(),
+		log.FileField: mgr.config.PortsFile,
+	})
+	contextLogger.Debug("writing ports file")
+
+	ports := make(map[string]int)
+	for apiName, api := range mgr.apis {
+		ports[apiName] = api.GetPort()
+	}
+
+	contents, err := getPortsFileContents(mgr.config.PortsFile, ports)
+	if err != nil {
+		contextLogger.WithError(err).Error("error creating contents of ports file")
+		return
+	}
+
+	if err := mgr.file.WriteFile(mgr.config.PortsFile, contents, portsFilePerm); err != nil {
+		contextLogger.WithError(err).Error("error writing ports file")
+		return
+	}
+
+	contextLogger.Info("successfully wrote ports file")
+}
+
+func getPortsFileContents(portsFile string, ports map[string]int) ([]byte, error) {
+	if strings.EqualFold(filepath.Ext(portsFile), portsFileJSONExt) {
+		return json.MarshalIndent(ports, "", "  ")
+	}
+
+	var lines []string
+	for apiName, port := range ports {
+		lines = append(lines, fmt.Sprintf("%s=%d", getPortEnvVarName(apiName), port))
+	}
+
+	sort.Strings(lines)
+	return []byte(strings.Join(lines, "\n") + "\n"), nil
+}
+
+func getPortEnvVarName(apiName string) string {
+	name := strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return '_'
+		}
+		return unicode.ToUpper(r)
+	}, apiName)
+	return name + portEnvVarSuffix
+}
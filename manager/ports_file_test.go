@@ -0,0 +1,68 @@
+package manager
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/wcsanders1/MockApiHub/api"
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/log"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func getPortsFileManager(portsFile string, fileOps *wrapper.FakeFileOps) *Manager {
+	customersAPI := new(api.FakeAPI)
+	customersAPI.On("GetPort").Return(5001)
+	studentsAPI := new(api.FakeAPI)
+	studentsAPI.On("GetPort").Return(5002)
+	return &Manager{
+		apis: map[string]api.IAPI{
+			"exampleCustomersApi":  customersAPI,
+			"example-students.Api": studentsAPI,
+		},
+		config: &config.AppConfig{PortsFile: portsFile},
+		file:   fileOps,
+		log:    log.GetFakeLogger(),
+	}
+}
+
+func TestWritePortsFile_WritesEnvFile_WhenPortsFileIsNotJSON(t *testing.T) {
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("WriteFile", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mgr := getPortsFileManager("ports.env", fileOps)
+
+	mgr.writePortsFile()
+
+	fileOps.AssertCalled(t, "WriteFile", "ports.env", []byte("EXAMPLECUSTOMERSAPI_PORT=5001\nEXAMPLE_STUDENTS_API_PORT=5002\n"), os.FileMode(portsFilePerm))
+}
+
+func TestWritePortsFile_WritesJSON_WhenPortsFileIsJSON(t *testing.T) {
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("WriteFile", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mgr := getPortsFileManager("ports.JSON", fileOps)
+
+	mgr.writePortsFile()
+
+	fileOps.AssertCalled(t, "WriteFile", "ports.JSON", []byte("{\n  \"example-students.Api\": 5002,\n  \"exampleCustomersApi\": 5001\n}"), os.FileMode(portsFilePerm))
+}
+
+func TestWritePortsFile_DoesNotWrite_WhenNoPortsFileConfigured(t *testing.T) {
+	fileOps := new(wrapper.FakeFileOps)
+	mgr := getPortsFileManager("", fileOps)
+
+	mgr.writePortsFile()
+
+	fileOps.AssertNotCalled(t, "WriteFile", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWritePortsFile_DoesNotPanic_WhenWriteFails(t *testing.T) {
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("WriteFile", mock.Anything, mock.Anything, mock.Anything).Return(errors.New(""))
+	mgr := getPortsFileManager("ports.env", fileOps)
+
+	assert.NotPanics(t, mgr.writePortsFile)
+}
@@ -0,0 +1,353 @@
+package mockhub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/wcsanders1/MockApiHub/config"
+)
+
+type (
+	// Builder builds mock API configurations in Go, as an alternative to configuration files.
+	//
+	// Example:
+	//
+	//	hub := mockhub.NewBuilder()
+	//	hub.API("customers").BaseURL("customersApi").
+	//		GET("customers/:id").Status(200).JSON(customer).Header("X-Version", "2").
+	//		POST("customers").Status(201).
+	//		GET("orders").JSON(firstPage).Then().JSON(lastPage)
+	//	h := hub.NewTest(t)
+	Builder struct {
+		apis  map[string]*APIBuilder
+		names []string
+		err   error
+	}
+
+	// APIBuilder builds the configuration of one mock API.
+	APIBuilder struct {
+		builder   *Builder
+		config    config.APIConfig
+		endpoints []*EndpointBuilder
+	}
+
+	// EndpointBuilder builds the configuration of one endpoint. Methods that describe a response,
+	// such as Status, Body and Header, apply to the response begun by the last call to Then.
+	EndpointBuilder struct {
+		api       *APIBuilder
+		name      string
+		endpoint  config.Endpoint
+		responses []config.Response
+	}
+)
+
+// NewBuilder returns a new Builder.
+func NewBuilder() *Builder {
+	return &Builder{
+		apis: make(map[string]*APIBuilder),
+	}
+}
+
+// API returns the builder for the mock API with the name provided, creating it if needed. The
+// mock API listens on a free port unless Port is called.
+func (b *Builder) API(name string) *APIBuilder {
+	if api, exists := b.apis[name]; exists {
+		return api
+	}
+
+	api := &APIBuilder{builder: b}
+	b.apis[name] = api
+	b.names = append(b.names, name)
+	return api
+}
+
+// Configs returns the configurations built, keyed by mock API name, or the first error
+// encountered while building them.
+func (b *Builder) Configs() (map[string]config.APIConfig, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	configs := make(map[string]config.APIConfig)
+	for _, name := range b.names {
+		configs[name] = b.apis[name].build()
+	}
+	return configs, nil
+}
+
+// Start starts a hub serving the mock APIs built. Files named by the endpoints are read from
+// dataDir.
+func (b *Builder) Start(appConfig config.AppConfig, dataDir string) (*Hub, error) {
+	configs, err := b.Configs()
+	if err != nil {
+		return nil, err
+	}
+	return Start(appConfig, configs, dataDir)
+}
+
+// NewTest starts a hub on free ports serving the mock APIs built, failing the test if the hub
+// cannot start and closing the hub when the test finishes. Files named by the endpoints are read
+// from the testdata directory.
+func (b *Builder) NewTest(t testing.TB) *Hub {
+	t.Helper()
+
+	configs, err := b.Configs()
+	if err != nil {
+		t.Fatalf("error building mock APIs: %v", err)
+	}
+	return NewTest(t, configs, "testdata")
+}
+
+func (b *Builder) setError(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Port sets the port on which the mock API listens.
+func (api *APIBuilder) Port(port int) *APIBuilder {
+	api.config.HTTP.Port = config.Port(port)
+	return api
+}
+
+// BaseURL sets the mock API's base URL.
+func (api *APIBuilder) BaseURL(baseURL string) *APIBuilder {
+	api.config.BaseURL = baseURL
+	return api
+}
+
+// VirtualHost sets the host name for which a shared port serves the mock API.
+func (api *APIBuilder) VirtualHost(host string) *APIBuilder {
+	api.config.VirtualHost = host
+	return api
+}
+
+// Shared makes the mock API share its port with other mock APIs.
+func (api *APIBuilder) Shared() *APIBuilder {
+	api.config.HTTP.Shared = true
+	return api
+}
+
+// CaseSensitive makes the mock API's routes case sensitive.
+func (api *APIBuilder) CaseSensitive() *APIBuilder {
+	api.config.CaseSensitive = true
+	return api
+}
+
+// StrictTrailingSlash makes the mock API distinguish routes with a trailing slash from routes without.
+func (api *APIBuilder) StrictTrailingSlash() *APIBuilder {
+	api.config.StrictTrailingSlash = true
+	return api
+}
+
+// Endpoint adds an endpoint handling requests with the method and path provided.
+func (api *APIBuilder) Endpoint(method, path string) *EndpointBuilder {
+	endpoint := &EndpointBuilder{
+		api: api,
+		endpoint: config.Endpoint{
+			Method: method,
+			Path:   path,
+		},
+		responses: make([]config.Response, 1),
+	}
+	api.endpoints = append(api.endpoints, endpoint)
+	return endpoint
+}
+
+// GET adds an endpoint handling GET requests to the path provided.
+func (api *APIBuilder) GET(path string) *EndpointBuilder {
+	return api.Endpoint(http.MethodGet, path)
+}
+
+// POST adds an endpoint handling POST requests to the path provided.
+func (api *APIBuilder) POST(path string) *EndpointBuilder {
+	return api.Endpoint(http.MethodPost, path)
+}
+
+// PUT adds an endpoint handling PUT requests to the path provided.
+func (api *APIBuilder) PUT(path string) *EndpointBuilder {
+	return api.Endpoint(http.MethodPut, path)
+}
+
+// PATCH adds an endpoint handling PATCH requests to the path provided.
+func (api *APIBuilder) PATCH(path string) *EndpointBuilder {
+	return api.Endpoint(http.MethodPatch, path)
+}
+
+// DELETE adds an endpoint handling DELETE requests to the path provided.
+func (api *APIBuilder) DELETE(path string) *EndpointBuilder {
+	return api.Endpoint(http.MethodDelete, path)
+}
+
+func (api *APIBuilder) build() config.APIConfig {
+	apiConfig := api.config
+	apiConfig.Endpoints = make(map[string]config.Endpoint)
+	for i, endpoint := range api.endpoints {
+		name := endpoint.name
+		if len(name) == 0 {
+			name = fmt.Sprintf("%s %s", endpoint.endpoint.Method, endpoint.endpoint.Path)
+		}
+		if _, exists := apiConfig.Endpoints[name]; exists {
+			name = fmt.Sprintf("%s #%d", name, i+1)
+		}
+		apiConfig.Endpoints[name] = endpoint.build()
+	}
+	return apiConfig
+}
+
+// Name sets the endpoint's name, which otherwise is its method and path.
+func (endpoint *EndpointBuilder) Name(name string) *EndpointBuilder {
+	endpoint.name = name
+	return endpoint
+}
+
+// Query requires that the request's query string have the key provided with the value provided.
+func (endpoint *EndpointBuilder) Query(key, value string) *EndpointBuilder {
+	endpoint.endpoint.Query = append(endpoint.endpoint.Query, config.Matcher{Key: key, Value: value})
+	return endpoint
+}
+
+// QueryMatches requires that the request's query string have the key provided with a value
+// matching the regular expression provided.
+func (endpoint *EndpointBuilder) QueryMatches(key, regex string) *EndpointBuilder {
+	endpoint.endpoint.Query = append(endpoint.endpoint.Query, config.Matcher{Key: key, Regex: regex})
+	return endpoint
+}
+
+// QueryAbsent requires that the request's query string not have the key provided.
+func (endpoint *EndpointBuilder) QueryAbsent(key string) *EndpointBuilder {
+	endpoint.endpoint.Query = append(endpoint.endpoint.Query, config.Matcher{Key: key, Absent: true})
+	return endpoint
+}
+
+// RequestHeader requires that the request have the header provided with the value provided.
+func (endpoint *EndpointBuilder) RequestHeader(key, value string) *EndpointBuilder {
+	endpoint.endpoint.RequestHeaders = append(endpoint.endpoint.RequestHeaders, config.Matcher{Key: key, Value: value})
+	return endpoint
+}
+
+// RequestHeaderMatches requires that the request have the header provided with a value matching
+// the regular expression provided.
+func (endpoint *EndpointBuilder) RequestHeaderMatches(key, regex string) *EndpointBuilder {
+	endpoint.endpoint.RequestHeaders = append(endpoint.endpoint.RequestHeaders, config.Matcher{Key: key, Regex: regex})
+	return endpoint
+}
+
+// RequestHeaderAbsent requires that the request not have the header provided.
+func (endpoint *EndpointBuilder) RequestHeaderAbsent(key string) *EndpointBuilder {
+	endpoint.endpoint.RequestHeaders = append(endpoint.endpoint.RequestHeaders, config.Matcher{Key: key, Absent: true})
+	return endpoint
+}
+
+// RequestSchema validates request bodies against the JSON schema file provided.
+func (endpoint *EndpointBuilder) RequestSchema(schemaFile string) *EndpointBuilder {
+	endpoint.endpoint.RequestSchema = schemaFile
+	return endpoint
+}
+
+// AllowCORS allows requests from any origin.
+func (endpoint *EndpointBuilder) AllowCORS() *EndpointBuilder {
+	endpoint.endpoint.AllowCORS = true
+	return endpoint
+}
+
+// EnforceValidJSON makes the endpoint return an error rather than a body that is not valid JSON.
+func (endpoint *EndpointBuilder) EnforceValidJSON() *EndpointBuilder {
+	endpoint.endpoint.EnforceValidJSON = true
+	return endpoint
+}
+
+// Status sets the status code of the current response.
+func (endpoint *EndpointBuilder) Status(statusCode int) *EndpointBuilder {
+	endpoint.current().HTTPStatusCode = statusCode
+	return endpoint
+}
+
+// Header adds a header to the current response.
+func (endpoint *EndpointBuilder) Header(key, value string) *EndpointBuilder {
+	response := endpoint.current()
+	response.Headers = append(response.Headers, config.Header{Key: key, Value: value})
+	return endpoint
+}
+
+// Body sets the body of the current response.
+func (endpoint *EndpointBuilder) Body(body string) *EndpointBuilder {
+	endpoint.current().Body = body
+	return endpoint
+}
+
+// JSON sets the body of the current response to v encoded as JSON, and sets its Content-Type
+// header to application/json.
+func (endpoint *EndpointBuilder) JSON(v interface{}) *EndpointBuilder {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		endpoint.api.builder.setError(fmt.Errorf("error encoding JSON body for %s %s: %v", endpoint.endpoint.Method, endpoint.endpoint.Path, err))
+		return endpoint
+	}
+
+	return endpoint.Body(string(bytes)).Header("Content-Type", "application/json")
+}
+
+// File sets the current response to serve the file provided.
+func (endpoint *EndpointBuilder) File(file string) *EndpointBuilder {
+	endpoint.current().File = file
+	return endpoint
+}
+
+// Then begins the next response in the endpoint's sequence of responses. Each request receives the
+// next response in turn, and the last response is repeated once the others have been served.
+func (endpoint *EndpointBuilder) Then() *EndpointBuilder {
+	endpoint.responses = append(endpoint.responses, config.Response{})
+	return endpoint
+}
+
+// Endpoint adds another endpoint to the mock API.
+func (endpoint *EndpointBuilder) Endpoint(method, path string) *EndpointBuilder {
+	return endpoint.api.Endpoint(method, path)
+}
+
+// GET adds an endpoint handling GET requests to the mock API.
+func (endpoint *EndpointBuilder) GET(path string) *EndpointBuilder {
+	return endpoint.api.GET(path)
+}
+
+// POST adds an endpoint handling POST requests to the mock API.
+func (endpoint *EndpointBuilder) POST(path string) *EndpointBuilder {
+	return endpoint.api.POST(path)
+}
+
+// PUT adds an endpoint handling PUT requests to the mock API.
+func (endpoint *EndpointBuilder) PUT(path string) *EndpointBuilder {
+	return endpoint.api.PUT(path)
+}
+
+// PATCH adds an endpoint handling PATCH requests to the mock API.
+func (endpoint *EndpointBuilder) PATCH(path string) *EndpointBuilder {
+	return endpoint.api.PATCH(path)
+}
+
+// DELETE adds an endpoint handling DELETE requests to the mock API.
+func (endpoint *EndpointBuilder) DELETE(path string) *EndpointBuilder {
+	return endpoint.api.DELETE(path)
+}
+
+func (endpoint *EndpointBuilder) current() *config.Response {
+	return &endpoint.responses[len(endpoint.responses)-1]
+}
+
+func (endpoint *EndpointBuilder) build() config.Endpoint {
+	result := endpoint.endpoint
+	if len(endpoint.responses) > 1 {
+		result.Sequence = append([]config.Response{}, endpoint.responses...)
+		return result
+	}
+
+	response := endpoint.responses[0]
+	result.File = response.File
+	result.Body = response.Body
+	result.HTTPStatusCode = response.HTTPStatusCode
+	result.Headers = response.Headers
+	return result
+}
@@ -0,0 +1,104 @@
+package mockhub
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/wcsanders1/MockApiHub/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigs_ReturnsAPIConfigs_WhenBuilt(t *testing.T) {
+	hub := NewBuilder()
+	hub.API("customers").Port(5001).BaseURL("customersApi").
+		GET("customers/:id").Status(200).JSON(map[string]int{"id": 1}).Header("X-Version", "2").
+		POST("customers").Name("createCustomer").Status(201).RequestHeader("Authorization", "token")
+
+	result, err := hub.Configs()
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal(config.APIConfig{
+		BaseURL: "customersApi",
+		HTTP:    config.HTTP{Port: 5001},
+		Endpoints: map[string]config.Endpoint{
+			"GET customers/:id": config.Endpoint{
+				Method:         "GET",
+				Path:           "customers/:id",
+				HTTPStatusCode: 200,
+				Body:           `{"id":1}`,
+				Headers: []config.Header{
+					{Key: "Content-Type", Value: "application/json"},
+					{Key: "X-Version", Value: "2"},
+				},
+			},
+			"createCustomer": config.Endpoint{
+				Method:         "POST",
+				Path:           "customers",
+				HTTPStatusCode: 201,
+				RequestHeaders: []config.Matcher{{Key: "Authorization", Value: "token"}},
+			},
+		},
+	}, result["customers"])
+}
+
+func TestConfigs_ReturnsSequence_WhenThenCalled(t *testing.T) {
+	hub := NewBuilder()
+	hub.API("orders").GET("orders").Status(202).Then().Status(200).File("orders.json")
+
+	result, _ := hub.Configs()
+
+	assert.Equal(t, []config.Response{
+		{HTTPStatusCode: 202},
+		{HTTPStatusCode: 200, File: "orders.json"},
+	}, result["orders"].Endpoints["GET orders"].Sequence)
+}
+
+func TestConfigs_NamesEndpointsDistinctly_WhenMethodAndPathRepeat(t *testing.T) {
+	hub := NewBuilder()
+	hub.API("orders").
+		GET("orders").Query("page", "1").Body("first").
+		GET("orders").Query("page", "2").Body("second")
+
+	result, _ := hub.Configs()
+
+	assert := assert.New(t)
+	assert.Equal(2, len(result["orders"].Endpoints))
+	assert.Equal("second", result["orders"].Endpoints["GET orders #2"].Body)
+}
+
+func TestConfigs_ReturnsError_WhenJSONCannotBeEncoded(t *testing.T) {
+	hub := NewBuilder()
+	hub.API("orders").GET("orders").JSON(make(chan int))
+
+	result, err := hub.Configs()
+
+	assert := assert.New(t)
+	assert.Nil(result)
+	assert.Error(err)
+}
+
+func TestAPI_ReturnsSameBuilder_WhenNameRepeats(t *testing.T) {
+	hub := NewBuilder()
+
+	assert.Equal(t, hub.API("orders"), hub.API("orders"))
+}
+
+func TestBuilderNewTest_ServesResponsesInTurn_WhenSequenceBuilt(t *testing.T) {
+	hub := NewBuilder()
+	hub.API("orders").BaseURL("ordersApi").
+		GET("orders").Status(http.StatusAccepted).Body("pending").
+		Then().JSON([]string{"order"})
+	h := hub.NewTest(t)
+	url := h.BaseURL("orders") + "/orders"
+
+	firstStatus, firstBody := get(t, url)
+	secondStatus, secondBody := get(t, url)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusAccepted, firstStatus)
+	assert.Equal("pending", firstBody)
+	assert.Equal(http.StatusOK, secondStatus)
+	assert.Equal(`["order"]`, secondBody)
+}
@@ -0,0 +1,144 @@
+/*
+Package mockhub runs a mock API hub inside the current process so that Go tests can use mock APIs
+without starting the hub executable.
+
+Example:
+
+	func TestGetCustomers(t *testing.T) {
+		hub := mockhub.NewTest(t, map[string]config.APIConfig{
+			"customers": {
+				BaseURL: "customersApi",
+				Endpoints: map[string]config.Endpoint{
+					"getCustomers": {Path: "customers", File: "customers.json", Method: "GET"},
+				},
+			},
+		}, "testdata")
+
+		resp, err := http.Get(hub.BaseURL("customers") + "/customers")
+		...
+	}
+
+*/
+package mockhub
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/manager"
+)
+
+const host = "127.0.0.1"
+
+// Hub is a mock API hub running in the current process.
+type Hub struct {
+	mgr *manager.Manager
+}
+
+// Start starts a hub serving the mock APIs provided, keyed by name. Files named by the mock APIs'
+// endpoints are read from dataDir. A port of 0 in the app configuration or in a mock API's
+// configuration means that a free port is chosen. Start returns once every server is listening.
+func Start(appConfig config.AppConfig, apiConfigs map[string]config.APIConfig, dataDir string) (*Hub, error) {
+	if apiConfigs == nil {
+		apiConfigs = make(map[string]config.APIConfig)
+	}
+
+	mgr, err := manager.NewManagerFromConfigs(&appConfig, apiConfigs, dataDir)
+	if err != nil {
+		return nil, err
+	}
+	return start(mgr)
+}
+
+// StartFromDir starts a hub serving the mock APIs in apiDir, which is laid out like the mockApis
+// directory. Mock APIs are named by their directories.
+func StartFromDir(appConfig config.AppConfig, apiDir string) (*Hub, error) {
+	mgr, err := manager.NewManagerForDir(&appConfig, apiDir)
+	if err != nil {
+		return nil, err
+	}
+	return start(mgr)
+}
+
+// NewTest starts a hub on free ports serving the mock APIs provided, as Start does, failing the
+// test if the hub cannot start and closing the hub when the test finishes.
+func NewTest(t testing.TB, apiConfigs map[string]config.APIConfig, dataDir string) *Hub {
+	t.Helper()
+
+	hub, err := Start(config.AppConfig{}, apiConfigs, dataDir)
+	if err != nil {
+		t.Fatalf("error starting mock API hub: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := hub.Close(); err != nil {
+			t.Errorf("error closing mock API hub: %v", err)
+		}
+	})
+	return hub
+}
+
+// NewTestFromDir starts a hub on a free port serving the mock APIs in apiDir, as StartFromDir does,
+// failing the test if the hub cannot start and closing the hub when the test finishes.
+func NewTestFromDir(t testing.TB, apiDir string) *Hub {
+	t.Helper()
+
+	hub, err := StartFromDir(config.AppConfig{}, apiDir)
+	if err != nil {
+		t.Fatalf("error starting mock API hub: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := hub.Close(); err != nil {
+			t.Errorf("error closing mock API hub: %v", err)
+		}
+	})
+	return hub
+}
+
+func start(mgr *manager.Manager) (*Hub, error) {
+	if err := mgr.StartMockAPIHubInBackground(); err != nil {
+		mgr.Shutdown()
+		return nil, err
+	}
+	return &Hub{mgr: mgr}, nil
+}
+
+// URL returns the URL of the hub API, e.g., http://127.0.0.1:5000.
+func (hub *Hub) URL() string {
+	return getURL(hub.mgr.GetHubPort(), "")
+}
+
+// Port returns the port of the mock API with the name provided, or 0 if there is no such mock API.
+func (hub *Hub) Port(name string) int {
+	api, exists := hub.mgr.GetAPI(name)
+	if !exists {
+		return 0
+	}
+	return api.GetPort()
+}
+
+// BaseURL returns the URL of the mock API with the name provided, including its base URL, e.g.,
+// http://127.0.0.1:5001/customersApi. It returns an empty string if there is no such mock API.
+func (hub *Hub) BaseURL(name string) string {
+	api, exists := hub.mgr.GetAPI(name)
+	if !exists {
+		return ""
+	}
+	return getURL(api.GetPort(), api.GetBaseURL())
+}
+
+// Close shuts down the hub and its mock APIs.
+func (hub *Hub) Close() error {
+	return hub.mgr.Shutdown()
+}
+
+func getURL(port int, baseURL string) string {
+	url := fmt.Sprintf("http://%s:%d", host, port)
+	if baseURL = strings.Trim(baseURL, "/"); len(baseURL) > 0 {
+		url = fmt.Sprintf("%s/%s", url, baseURL)
+	}
+	return url
+}
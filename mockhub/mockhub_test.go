@@ -0,0 +1,122 @@
+package mockhub
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/wcsanders1/MockApiHub/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var customersAPIConfig = config.APIConfig{
+	BaseURL: "customersApi",
+	Endpoints: map[string]config.Endpoint{
+		"getCustomers": config.Endpoint{
+			Path:   "customers",
+			File:   "customers.json",
+			Method: "GET",
+		},
+	},
+}
+
+func get(t *testing.T, url string) (int, string) {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestNewTest_ServesMockAPI_WhenGivenConfigs(t *testing.T) {
+	hub := NewTest(t, map[string]config.APIConfig{"customers": customersAPIConfig}, "testdata")
+
+	status, body := get(t, hub.BaseURL("customers")+"/customers")
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusOK, status)
+	assert.JSONEq(`[{"id": 1, "name": "Ada"}]`, body)
+	assert.NotZero(hub.Port("customers"))
+}
+
+func TestNewTestFromDir_ServesMockAPI_WhenGivenDirectory(t *testing.T) {
+	hub := NewTestFromDir(t, "testdata/mockApis")
+
+	status, body := get(t, hub.BaseURL("customersApi")+"/customers")
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusOK, status)
+	assert.JSONEq(`[{"id": 1, "name": "Ada"}]`, body)
+}
+
+func TestURL_ServesHubAPI_WhenHubStarted(t *testing.T) {
+	hub := NewTest(t, map[string]config.APIConfig{"customers": customersAPIConfig}, "testdata")
+
+	status, body := get(t, hub.URL()+"/show-registered-mock-api?name=customers")
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusOK, status)
+	assert.Contains(body, `"BaseURL":"customersApi"`)
+}
+
+func TestBaseURL_ReturnsEmptyString_WhenAPIUnknown(t *testing.T) {
+	hub := NewTest(t, nil, "testdata")
+
+	assert := assert.New(t)
+	assert.Empty(hub.BaseURL("unknown"))
+	assert.Zero(hub.Port("unknown"))
+}
+
+func TestClose_StopsMockAPIs_WhenCalled(t *testing.T) {
+	hub, err := Start(config.AppConfig{}, map[string]config.APIConfig{"customers": customersAPIConfig}, "testdata")
+	assert.NoError(t, err)
+	url := hub.BaseURL("customers") + "/customers"
+
+	closeErr := hub.Close()
+	_, getErr := http.Get(url)
+
+	assert := assert.New(t)
+	assert.NoError(closeErr)
+	assert.Error(getErr)
+}
+
+func TestStart_ServesMockAPI_WhenPortIsFixed(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	apiConfig := customersAPIConfig
+	apiConfig.HTTP = config.HTTP{Port: config.Port(port)}
+
+	hub, err := Start(config.AppConfig{}, map[string]config.APIConfig{"customers": apiConfig}, "testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hub.Close()
+	status, _ := get(t, hub.BaseURL("customers")+"/customers")
+
+	assert := assert.New(t)
+	assert.Equal(port, hub.Port("customers"))
+	assert.Equal(http.StatusOK, status)
+}
+
+func TestStart_ReturnsError_WhenHubPortIsNegative(t *testing.T) {
+	appConfig := config.AppConfig{
+		HTTP: config.HTTP{
+			Port: -1,
+		},
+	}
+
+	hub, err := Start(appConfig, nil, "testdata")
+
+	assert := assert.New(t)
+	assert.Nil(hub)
+	assert.Error(err)
+}
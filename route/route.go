@@ -23,8 +23,10 @@ type (
 
 	// Tree contains routing for an API in a tree format.
 	Tree struct {
-		routeType routeType
-		branches  map[string]*Tree
+		routeType           routeType
+		branches            map[string]*Tree
+		caseSensitive       bool
+		strictTrailingSlash bool
 	}
 
 	// HTTPError is the error type returned when an HTTP error occurs.
@@ -38,6 +40,10 @@ const (
 	incomplete  routeType = 0
 	complete    routeType = 1
 	notFoundMsg string    = "route not found"
+
+	// trailingSlashFrag is the branch key marking a route that ends in a slash. A URL fragment
+	// can never contain a slash, so this key cannot collide with a real fragment.
+	trailingSlashFrag = "/"
 )
 
 // NewHTTPError returns a reference to a NewHTTPError.
@@ -60,18 +66,28 @@ func NewRouteTree() *Tree {
 	}
 }
 
+// NewRouteTreeWithOptions returns a new instance of Tree. If caseSensitive is true, routes and
+// route parameters keep their case; if strictTrailingSlash is true, a route ending in a slash
+// is distinct from the same route without one.
+func NewRouteTreeWithOptions(caseSensitive, strictTrailingSlash bool) *Tree {
+	tree := NewRouteTree()
+	tree.caseSensitive = caseSensitive
+	tree.strictTrailingSlash = strictTrailingSlash
+	return tree
+}
+
 // AddRoute adds a route to the tree.
 func (tree *Tree) AddRoute(url string) (string, error) {
 	if len(url) == 0 {
 		return "", errors.New("no url provided")
 	}
 
-	url = strings.ToLower(url)
+	url = tree.normalizeURL(url)
 	if existingRoute, _, _ := tree.GetRoute(url); len(existingRoute) > 0 {
 		return "", fmt.Errorf("route %s already registered", url)
 	}
 
-	fragments, _ := str.GetURLFragments(url)
+	fragments, _ := tree.getURLFragments(url)
 
 	if duplicateParamsExist(fragments) {
 		return "", fmt.Errorf("route has duplicate parameters: %v", fragments)
@@ -85,13 +101,13 @@ func (tree *Tree) AddRoute(url string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return path.Clean(route), nil
+	return route, nil
 }
 
 // GetRoute returns a route if it exists in the tree.
 func (tree *Tree) GetRoute(url string) (string, map[string]string, error) {
-	url = strings.ToLower(url)
-	fragments, err := str.GetURLFragments(url)
+	url = tree.normalizeURL(url)
+	fragments, err := tree.getURLFragments(url)
 	if err != nil {
 		return "", nil, err
 	}
@@ -100,7 +116,32 @@ func (tree *Tree) GetRoute(url string) (string, map[string]string, error) {
 	if err != nil {
 		return "", nil, err
 	}
-	return path.Clean(route), params, nil
+
+	route = path.Clean(route)
+	if fragments[len(fragments)-1] == trailingSlashFrag {
+		route += "/"
+	}
+	return route, params, nil
+}
+
+func (tree *Tree) normalizeURL(url string) string {
+	if tree.caseSensitive {
+		return url
+	}
+	return strings.ToLower(url)
+}
+
+func (tree *Tree) getURLFragments(url string) ([]string, error) {
+	fragments, err := str.GetURLFragments(url)
+	if err != nil {
+		return nil, err
+	}
+
+	last := len(fragments) - 1
+	if tree.strictTrailingSlash && last > 0 && len(fragments[last]) == 0 {
+		fragments[last] = trailingSlashFrag
+	}
+	return fragments, nil
 }
 
 func (tree *Tree) getRouteByFragments(fragments []string, params map[string]string) (string, map[string]string, error) {
@@ -203,6 +203,65 @@ func TestGetRoute_ReturnsError_WhenProvidedNothing(t *testing.T) {
 	assert.Error(err)
 }
 
+func TestGetRoute_PreservesCase_WhenTreeIsCaseSensitive(t *testing.T) {
+	routeTree := NewRouteTreeWithOptions(true, false)
+	routeTree.AddRoute("Customers/:id")
+
+	result, params, err := routeTree.GetRoute("Customers/ABC123")
+	_, _, lowerErr := routeTree.GetRoute("customers/ABC123")
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal("Customers/:id", result)
+	assert.Equal("ABC123", params["id"])
+	assert.Error(lowerErr)
+}
+
+func TestGetRoute_IgnoresCase_WhenTreeIsNotCaseSensitive(t *testing.T) {
+	routeTree := NewRouteTreeWithOptions(false, false)
+	routeTree.AddRoute("Customers/:id")
+
+	result, params, err := routeTree.GetRoute("CUSTOMERS/ABC123")
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal("customers/:id", result)
+	assert.Equal("abc123", params["id"])
+}
+
+func TestGetRoute_DistinguishesTrailingSlash_WhenTreeHasStrictTrailingSlash(t *testing.T) {
+	routeTree := NewRouteTreeWithOptions(false, true)
+	routeTree.AddRoute("students")
+	routeTree.AddRoute("students/")
+	routeTree.AddRoute(":id/grades/")
+
+	noSlashResult, _, noSlashErr := routeTree.GetRoute("students")
+	slashResult, _, slashErr := routeTree.GetRoute("students/")
+	paramResult, params, paramErr := routeTree.GetRoute("12/grades/")
+	_, _, missingErr := routeTree.GetRoute("12/grades")
+
+	assert := assert.New(t)
+	assert.Nil(noSlashErr)
+	assert.Nil(slashErr)
+	assert.Nil(paramErr)
+	assert.Error(missingErr)
+	assert.Equal("students", noSlashResult)
+	assert.Equal("students/", slashResult)
+	assert.Equal(":id/grades/", paramResult)
+	assert.Equal("12", params["id"])
+}
+
+func TestAddRoute_ReturnsError_WhenTrailingSlashRouteRegisteredTwice(t *testing.T) {
+	routeTree := NewRouteTreeWithOptions(false, true)
+	routeTree.AddRoute("students/")
+
+	result, err := routeTree.AddRoute("students/")
+
+	assert := assert.New(t)
+	assert.Error(err)
+	assert.Empty(result)
+}
+
 func TestAddRouteToExistingBranch_ReturnsNil_WhenRouteValid(t *testing.T) {
 	frags := strings.Split("test/route", "/")
 	routeTree := NewRouteTree()
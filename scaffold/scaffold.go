@@ -0,0 +1,200 @@
+//Package scaffold creates the directory, configuration file and sample data file of a new mock API.
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/constants"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+)
+
+const (
+	dirPerm  = 0755
+	filePerm = 0644
+)
+
+type (
+	// APIOptions describe the mock API to create.
+	APIOptions struct {
+		Name    string
+		Port    config.Port
+		BaseURL string
+	}
+
+	// Scaffolder creates new mock APIs.
+	Scaffolder struct {
+		file          wrapper.IFileOps
+		configManager config.IManager
+	}
+)
+
+const configTemplate = `# Configuration of the %[1]s mock API. Every key is optional except the endpoints' path and method.
+
+# Path prefixed to the path of every endpoint.
+baseUrl = %[2]q
+
+# Set to true to distinguish routes that differ only in case or in a trailing slash.
+caseSensitive = false
+strictTrailingSlash = false
+
+[http]
+# Port on which the mock API listens, or "auto" to choose a free port. Ports cannot be shared with
+# the hub or with other mock APIs unless shared is true.
+port = %[3]s
+shared = false
+useTLS = false
+certFile = ""
+keyFile = ""
+
+[log]
+loggingEnabled = false
+fileName = "testLogs/%[1]s/default.log"
+level = "info"
+
+[endpoints]
+
+    # Each endpoint is a table under endpoints. Files are read from this directory.
+    [endpoints.%[4]s]
+    path = %[5]q
+    file = %[6]q
+    method = "GET"
+    httpStatusCode = 200
+    enforceValidJSON = true
+
+        [[endpoints.%[4]s.headers]]
+        key = "Content-Type"
+        value = "application/json"
+`
+
+const sampleData = `[
+    {
+        "id": 1,
+        "name": "Sample"
+    }
+]
+`
+
+// NewScaffolder returns a reference to a new Scaffolder.
+func NewScaffolder() *Scaffolder {
+	return &Scaffolder{
+		file:          &wrapper.FileOps{},
+		configManager: config.NewConfigManager(),
+	}
+}
+
+// Create creates a mock API in the first of the application's mock API directories and returns the
+// path of its directory. The name is suffixed with Api if it does not already end in Api, and can be
+// prefixed with grouping directories, e.g., billing/invoices. Create refuses to overwrite an existing
+// mock API, to reuse the name of another mock API or to use a port taken by the hub or by another
+// mock API.
+func (s *Scaffolder) Create(appConfig *config.AppConfig, options APIOptions) (string, error) {
+	group, name := path.Split(strings.Trim(filepath.ToSlash(strings.TrimSpace(options.Name)), "/"))
+	if len(name) == 0 {
+		return "", errors.New("mock API name required")
+	}
+	for _, part := range strings.Split(strings.Trim(group, "/"), "/") {
+		if part == ".." || strings.HasPrefix(part, ".") || config.IsAPIDir(part) {
+			return "", fmt.Errorf("invalid grouping directory: %s", group)
+		}
+	}
+	if !config.IsAPIDir(name) {
+		name += constants.APIDirExt
+	}
+	if options.Port < 0 {
+		return "", fmt.Errorf("invalid port: %d", options.Port)
+	}
+
+	apiDirs := appConfig.APIDirs
+	if len(apiDirs) == 0 {
+		apiDirs = []string{constants.APIDir}
+	}
+
+	for _, apiDir := range apiDirs {
+		if _, err := s.file.Stat(filepath.Join(apiDir, group, name)); err == nil {
+			return "", fmt.Errorf("mock API %s already exists in %s", name, filepath.Join(apiDir, group))
+		}
+	}
+	if options.Port == appConfig.HTTP.Port && options.Port != 0 {
+		return "", fmt.Errorf("port %d is used by the hub", options.Port)
+	}
+	for _, apiDir := range apiDirs {
+		if err := s.ensureAvailable(apiDir, name, options.Port); err != nil {
+			return "", err
+		}
+	}
+
+	dir := filepath.Join(apiDirs[0], group, name)
+	if err := s.file.MkdirAll(dir, dirPerm); err != nil {
+		return "", err
+	}
+
+	resource := getResourceName(name)
+	dataFile := resource + ".json"
+	if err := s.file.WriteFile(filepath.Join(dir, dataFile), []byte(sampleData), filePerm); err != nil {
+		return "", err
+	}
+
+	baseURL := options.BaseURL
+	if len(baseURL) == 0 {
+		baseURL = name
+	}
+	contents := fmt.Sprintf(configTemplate, name, baseURL, formatPort(options.Port), "get"+strings.ToUpper(resource[:1])+resource[1:], resource, dataFile)
+	if err := s.file.WriteFile(filepath.Join(dir, name+".toml"), []byte(contents), filePerm); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// ensureAvailable returns an error if a mock API in apiDir, or in its grouping directories, has the
+// name or the port provided, or if apiDir exists but cannot be read.
+func (s *Scaffolder) ensureAvailable(apiDir, name string, port config.Port) error {
+	files, err := s.file.ReadDir(apiDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		apiConfig, err := s.configManager.GetAPIConfig(apiDir, file)
+		if err == config.ErrNotAPIDir {
+			if file.IsDir() && !strings.HasPrefix(file.Name(), ".") {
+				if err := s.ensureAvailable(filepath.Join(apiDir, file.Name()), name, port); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if file.Name() == name {
+			return fmt.Errorf("mock API %s already exists in %s", name, apiDir)
+		}
+		if err == nil && port != 0 && apiConfig.HTTP.Port == port {
+			return fmt.Errorf("port %d is used by mock API %s", port, file.Name())
+		}
+	}
+	return nil
+}
+
+func getResourceName(name string) string {
+	resource := strings.TrimSuffix(name, constants.APIDirExt)
+	if len(resource) == 0 {
+		return "items"
+	}
+	return strings.ToLower(resource[:1]) + resource[1:]
+}
+
+func formatPort(port config.Port) string {
+	if port == 0 {
+		return fmt.Sprintf("%q", config.AutoPort)
+	}
+	return fmt.Sprintf("%d", port)
+}
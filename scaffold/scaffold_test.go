@@ -0,0 +1,196 @@
+package scaffold
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/constants"
+	"github.com/wcsanders1/MockApiHub/fake"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func getScaffolder(existingPort config.Port) (*Scaffolder, *wrapper.FakeFileOps) {
+	fileInfo := new(fake.FileInfo)
+	fileInfo.On("Name").Return("exampleCustomersApi")
+
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("Stat", mock.AnythingOfType("string")).Return(fileInfo, errors.New("not found"))
+	fileOps.On("ReadDir", constants.APIDir).Return([]os.FileInfo{fileInfo}, nil)
+	fileOps.On("MkdirAll", mock.Anything, mock.Anything).Return(nil)
+	fileOps.On("WriteFile", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	configManager := new(config.FakeManager)
+	configManager.On("GetAPIConfig", constants.APIDir, fileInfo).Return(&config.APIConfig{HTTP: config.HTTP{Port: existingPort}}, nil)
+
+	return &Scaffolder{
+		file:          fileOps,
+		configManager: configManager,
+	}, fileOps
+}
+
+func TestCreate_WritesConfigAndData_WhenPortFree(t *testing.T) {
+	scaffolder, fileOps := getScaffolder(5001)
+	dir := filepath.Join(constants.APIDir, "ordersApi")
+
+	result, err := scaffolder.Create(&config.AppConfig{}, APIOptions{Name: "orders", Port: 5002})
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal(dir, result)
+	fileOps.AssertCalled(t, "MkdirAll", dir, os.FileMode(dirPerm))
+	fileOps.AssertCalled(t, "WriteFile", filepath.Join(dir, "orders.json"), []byte(sampleData), os.FileMode(filePerm))
+	fileOps.AssertCalled(t, "WriteFile", filepath.Join(dir, "ordersApi.toml"), mock.Anything, os.FileMode(filePerm))
+}
+
+func TestCreate_WritesDecodableConfig_WhenCreated(t *testing.T) {
+	scaffolder, fileOps := getScaffolder(5001)
+
+	scaffolder.Create(&config.AppConfig{}, APIOptions{Name: "ordersApi", Port: 5002, BaseURL: "api/orders"})
+
+	var contents []byte
+	for _, call := range fileOps.Calls {
+		if call.Method == "WriteFile" && filepath.Ext(call.Arguments.String(0)) == ".toml" {
+			contents = call.Arguments.Get(1).([]byte)
+		}
+	}
+	var apiConfig config.APIConfig
+	md, err := toml.Decode(string(contents), &apiConfig)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Empty(md.Undecoded())
+	assert.Equal(config.Port(5002), apiConfig.HTTP.Port)
+	assert.Equal("api/orders", apiConfig.BaseURL)
+	assert.Equal("orders.json", apiConfig.Endpoints["getOrders"].File)
+}
+
+func TestCreate_ReturnsError_WhenPortUsedByAPI(t *testing.T) {
+	scaffolder, fileOps := getScaffolder(5001)
+
+	result, err := scaffolder.Create(&config.AppConfig{}, APIOptions{Name: "orders", Port: 5001})
+
+	assert := assert.New(t)
+	assert.Error(err)
+	assert.Empty(result)
+	fileOps.AssertNotCalled(t, "MkdirAll", mock.Anything, mock.Anything)
+}
+
+func TestCreate_ReturnsError_WhenPortUsedByHub(t *testing.T) {
+	scaffolder, _ := getScaffolder(5001)
+	appConfig := &config.AppConfig{HTTP: config.HTTP{Port: 5000}}
+
+	_, err := scaffolder.Create(appConfig, APIOptions{Name: "orders", Port: 5000})
+
+	assert.Error(t, err)
+}
+
+func TestCreate_ReturnsError_WhenAPIExists(t *testing.T) {
+	fileInfo := new(fake.FileInfo)
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("Stat", filepath.Join("mockApis", "ordersApi")).Return(fileInfo, nil)
+	scaffolder := &Scaffolder{
+		file:          fileOps,
+		configManager: new(config.FakeManager),
+	}
+	appConfig := &config.AppConfig{APIDirs: []string{"mockApis"}}
+
+	_, err := scaffolder.Create(appConfig, APIOptions{Name: "ordersApi", Port: 0})
+
+	assert := assert.New(t)
+	assert.Error(err)
+	fileOps.AssertNotCalled(t, "WriteFile", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreate_ReturnsError_WhenNameEmpty(t *testing.T) {
+	scaffolder, _ := getScaffolder(5001)
+
+	_, err := scaffolder.Create(&config.AppConfig{}, APIOptions{Port: 5002})
+
+	assert.Error(t, err)
+}
+
+func TestCreate_CreatesAPIInGroup_WhenNameHasGroup(t *testing.T) {
+	scaffolder, fileOps := getScaffolder(5001)
+	dir := filepath.Join(constants.APIDir, "billing", "invoicesApi")
+
+	result, err := scaffolder.Create(&config.AppConfig{}, APIOptions{Name: "billing/invoices", Port: 5002})
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal(dir, result)
+	fileOps.AssertCalled(t, "MkdirAll", dir, os.FileMode(dirPerm))
+}
+
+func TestCreate_ReturnsError_WhenGroupInvalid(t *testing.T) {
+	scaffolder, _ := getScaffolder(5001)
+
+	_, err := scaffolder.Create(&config.AppConfig{}, APIOptions{Name: "../invoices", Port: 5002})
+
+	assert.Error(t, err)
+}
+
+func TestCreate_ReturnsError_WhenNameUsedInGroup(t *testing.T) {
+	groupDir := new(fake.FileInfo)
+	groupDir.On("Name").Return("billing")
+	groupDir.On("IsDir").Return(true)
+	invoicesDir := new(fake.FileInfo)
+	invoicesDir.On("Name").Return("invoicesApi")
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("Stat", mock.AnythingOfType("string")).Return(groupDir, errors.New("not found"))
+	fileOps.On("ReadDir", constants.APIDir).Return([]os.FileInfo{groupDir}, nil)
+	fileOps.On("ReadDir", filepath.Join(constants.APIDir, "billing")).Return([]os.FileInfo{invoicesDir}, nil)
+	configManager := new(config.FakeManager)
+	configManager.On("GetAPIConfig", constants.APIDir, groupDir).Return((*config.APIConfig)(nil), config.ErrNotAPIDir)
+	configManager.On("GetAPIConfig", filepath.Join(constants.APIDir, "billing"), invoicesDir).Return(&config.APIConfig{}, nil)
+	scaffolder := &Scaffolder{
+		file:          fileOps,
+		configManager: configManager,
+	}
+
+	_, err := scaffolder.Create(&config.AppConfig{}, APIOptions{Name: "invoices", Port: 0})
+
+	assert := assert.New(t)
+	assert.Error(err)
+	fileOps.AssertNotCalled(t, "MkdirAll", mock.Anything, mock.Anything)
+}
+
+func TestCreate_WritesConfigAndData_WhenAPIDirDoesNotExist(t *testing.T) {
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("Stat", mock.AnythingOfType("string")).Return(new(fake.FileInfo), errors.New("not found"))
+	fileOps.On("ReadDir", constants.APIDir).Return([]os.FileInfo(nil), &os.PathError{Op: "open", Path: constants.APIDir, Err: os.ErrNotExist})
+	fileOps.On("MkdirAll", mock.Anything, mock.Anything).Return(nil)
+	fileOps.On("WriteFile", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	scaffolder := &Scaffolder{
+		file:          fileOps,
+		configManager: new(config.FakeManager),
+	}
+
+	_, err := scaffolder.Create(&config.AppConfig{}, APIOptions{Name: "orders", Port: 5002})
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	fileOps.AssertCalled(t, "MkdirAll", filepath.Join(constants.APIDir, "ordersApi"), os.FileMode(dirPerm))
+}
+
+func TestCreate_ReturnsError_WhenAPIDirCannotBeRead(t *testing.T) {
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("Stat", mock.AnythingOfType("string")).Return(new(fake.FileInfo), errors.New("not found"))
+	fileOps.On("ReadDir", constants.APIDir).Return([]os.FileInfo(nil), &os.PathError{Op: "open", Path: constants.APIDir, Err: os.ErrPermission})
+	scaffolder := &Scaffolder{
+		file:          fileOps,
+		configManager: new(config.FakeManager),
+	}
+
+	_, err := scaffolder.Create(&config.AppConfig{}, APIOptions{Name: "orders", Port: 5002})
+
+	assert := assert.New(t)
+	assert.Error(err)
+	fileOps.AssertNotCalled(t, "MkdirAll", mock.Anything, mock.Anything)
+}
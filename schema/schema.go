@@ -0,0 +1,51 @@
+//Package schema provides functionality to validate JSON documents against JSON schemas.
+package schema
+
+import (
+	"encoding/json"
+
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// InvalidJSONViolation is the violation reported when a document is not JSON at all.
+const InvalidJSONViolation = "document is not valid JSON"
+
+// Validate validates a JSON document against the JSON schema in the file at schemaPath and returns
+// a description of each way the document violates the schema. An error is returned only if the
+// schema itself cannot be read or used.
+func Validate(schemaPath string, document []byte, file wrapper.IFileOps) ([]string, error) {
+	schemaFile, err := file.Open(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer schemaFile.Close()
+
+	schemaBytes, err := file.ReadAll(schemaFile)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonSchema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(schemaBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	var js json.RawMessage
+	if json.Unmarshal(document, &js) != nil {
+		return []string{InvalidJSONViolation}, nil
+	}
+
+	result, err := jsonSchema.Validate(gojsonschema.NewBytesLoader(document))
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []string
+	for _, resultError := range result.Errors() {
+		violations = append(violations, resultError.String())
+	}
+	return violations, nil
+}
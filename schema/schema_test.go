@@ -0,0 +1,90 @@
+package schema
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/wcsanders1/MockApiHub/wrapper"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var customerSchema = []byte(`{
+	"type": "object",
+	"required": ["name"],
+	"properties": {
+		"name": { "type": "string" },
+		"age": { "type": "integer" }
+	}
+}`)
+
+func getFakeFileOps(schemaBytes []byte, openErr, readErr error) *wrapper.FakeFileOps {
+	fileOps := new(wrapper.FakeFileOps)
+	fileOps.On("Open", mock.AnythingOfType("string")).Return(os.NewFile(1, "fakefile"), openErr)
+	fileOps.On("ReadAll", mock.AnythingOfType("*os.File")).Return(schemaBytes, readErr)
+	return fileOps
+}
+
+func TestValidate_ReturnsNoViolations_WhenDocumentMatchesSchema(t *testing.T) {
+	fileOps := getFakeFileOps(customerSchema, nil, nil)
+
+	result, err := Validate("testSchema", []byte(`{"name": "Ada", "age": 36}`), fileOps)
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Empty(result)
+	fileOps.AssertCalled(t, "Open", "testSchema")
+}
+
+func TestValidate_ReturnsViolations_WhenDocumentDoesNotMatchSchema(t *testing.T) {
+	fileOps := getFakeFileOps(customerSchema, nil, nil)
+
+	result, err := Validate("testSchema", []byte(`{"age": "old"}`), fileOps)
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal(2, len(result))
+}
+
+func TestValidate_ReturnsViolation_WhenDocumentIsNotJSON(t *testing.T) {
+	fileOps := getFakeFileOps(customerSchema, nil, nil)
+
+	result, err := Validate("testSchema", []byte(`{"name": `), fileOps)
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal([]string{InvalidJSONViolation}, result)
+}
+
+func TestValidate_ReturnsError_WhenSchemaIsInvalid(t *testing.T) {
+	fileOps := getFakeFileOps([]byte(`{"type": 12}`), nil, nil)
+
+	result, err := Validate("testSchema", []byte(`{}`), fileOps)
+
+	assert := assert.New(t)
+	assert.Error(err)
+	assert.Nil(result)
+}
+
+func TestValidate_ReturnsError_WhenOpenFileFails(t *testing.T) {
+	fileOps := getFakeFileOps(nil, errors.New(""), nil)
+
+	result, err := Validate("testSchema", []byte(`{}`), fileOps)
+
+	assert := assert.New(t)
+	assert.Error(err)
+	assert.Nil(result)
+	fileOps.AssertNotCalled(t, "ReadAll", mock.Anything)
+}
+
+func TestValidate_ReturnsError_WhenReadFileFails(t *testing.T) {
+	fileOps := getFakeFileOps([]byte{}, nil, errors.New(""))
+
+	result, err := Validate("testSchema", []byte(`{}`), fileOps)
+
+	assert := assert.New(t)
+	assert.Error(err)
+	assert.Nil(result)
+}
@@ -26,12 +26,30 @@ func GetURLFragments(url string) ([]string, error) {
 
 // CleanURL returns a URL in lowercase without a trailing or preceding slash.
 func CleanURL(url string) string {
+	return CleanURLWithOptions(url, false, false)
+}
+
+// CleanURLWithOptions returns a URL without a preceding slash, optionally preserving
+// its case and its trailing slash.
+func CleanURLWithOptions(url string, preserveCase, preserveTrailingSlash bool) string {
 	if len(url) == 0 {
 		return ""
 	}
 
-	urlLower := strings.ToLower(url)
-	return path.Clean(urlLower[1:])
+	if !preserveCase {
+		url = strings.ToLower(url)
+	}
+	return CleanPath(url[1:], preserveTrailingSlash)
+}
+
+// CleanPath returns the shortest path equivalent to the path passed to it, keeping
+// a trailing slash if preserveTrailingSlash is true.
+func CleanPath(p string, preserveTrailingSlash bool) string {
+	cleaned := path.Clean(p)
+	if preserveTrailingSlash && strings.HasSuffix(p, "/") && cleaned != "." && cleaned != "/" {
+		return cleaned + "/"
+	}
+	return cleaned
 }
 
 // RemoveColonFromParam removes the colon from a route parameter so it looks nice when logged.
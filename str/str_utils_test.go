@@ -50,6 +50,22 @@ func TestCleanURL_ReturnsNothing_WhenProvidedNothing(t *testing.T) {
 	assert.Empty(t, CleanURL(""))
 }
 
+func TestCleanURLWithOptions_PreservesCase_WhenPreserveCaseTrue(t *testing.T) {
+	assert.Equal(t, "TESt/Url", CleanURLWithOptions("/TESt/Url/", true, false))
+}
+
+func TestCleanURLWithOptions_PreservesTrailingSlash_WhenPreserveTrailingSlashTrue(t *testing.T) {
+	assert.Equal(t, "test/url/", CleanURLWithOptions("/TESt/Url/", false, true))
+}
+
+func TestCleanURLWithOptions_DoesNotAddTrailingSlash_WhenURLHasNone(t *testing.T) {
+	assert.Equal(t, "test/url", CleanURLWithOptions("/test/url", false, true))
+}
+
+func TestCleanPath_ReturnsRoot_WhenProvidedOnlySlash(t *testing.T) {
+	assert.Equal(t, "/", CleanPath("/", true))
+}
+
 func TestRemoveColonFromParam_ReturnsColonlessParam_WhenProvidedParam(t *testing.T) {
 	assert.Equal(t, "id", RemoveColonFromParam(":id"))
 }
@@ -0,0 +1,635 @@
+//Package validate checks the application configuration and the mock API configurations for mistakes
+//without starting the hub.
+package validate
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/wcsanders1/MockApiHub/api"
+	"github.com/wcsanders1/MockApiHub/config"
+	"github.com/wcsanders1/MockApiHub/constants"
+	"github.com/wcsanders1/MockApiHub/graphql"
+	"github.com/wcsanders1/MockApiHub/json"
+	"github.com/wcsanders1/MockApiHub/wrapper"
+	"github.com/wcsanders1/MockApiHub/xml"
+)
+
+type (
+	// Issue is a problem found in a configuration file. Line is 0 if the problem cannot be tied
+	// to a line of the file.
+	Issue struct {
+		File    string
+		Line    int
+		Message string
+	}
+
+	// Validator checks configuration files.
+	Validator struct {
+		file wrapper.IFileOps
+	}
+
+	apiPort struct {
+		name   string
+		file   string
+		line   int
+		port   config.Port
+		shared bool
+	}
+)
+
+var httpMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodOptions,
+	http.MethodTrace,
+}
+
+// NewValidator returns a reference to a new Validator.
+func NewValidator() *Validator {
+	return &Validator{
+		file: &wrapper.FileOps{},
+	}
+}
+
+// String formats the issue as file:line: message.
+func (issue Issue) String() string {
+	if issue.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", issue.File, issue.Line, issue.Message)
+	}
+	return fmt.Sprintf("%s: %s", issue.File, issue.Message)
+}
+
+// Validate checks the application configuration file named by the options and the configuration
+// of every mock API in the mock API directories, returning the issues found.
+func (v *Validator) Validate(options config.Options) []Issue {
+	var issues []Issue
+	path := options.AppConfigPath
+	if len(path) == 0 {
+		path = config.DefaultAppConfigPath
+	}
+
+	var appConfig config.AppConfig
+	contents := v.readContents(path)
+	md, err := config.DecodeFileStrictly(v.file, path, &appConfig)
+	if err != nil {
+		return append(issues, Issue{File: path, Message: err.Error()})
+	}
+	issues = append(issues, getUndecodedIssues(path, contents, md)...)
+
+	if err := options.Apply(&appConfig); err != nil {
+		issues = append(issues, Issue{File: path, Message: err.Error()})
+	}
+	if appConfig.HTTP.Port < 0 {
+		issues = append(issues, Issue{File: path, Line: findKeyLine(contents, "http", "port"), Message: fmt.Sprintf("invalid port: %d", appConfig.HTTP.Port)})
+	}
+	if appConfig.HTTP.UseTLS {
+		issues = append(issues, v.validateTLSFiles(path, contents, appConfig.HTTP)...)
+	}
+
+	apiDirs := appConfig.APIDirs
+	if len(apiDirs) == 0 {
+		apiDirs = []string{constants.APIDir}
+	}
+
+	var ports []apiPort
+	for _, apiDir := range apiDirs {
+		dirIssues, dirPorts := v.validateAPIDir(apiDir, &appConfig)
+		issues = append(issues, dirIssues...)
+		issues = append(issues, getNameIssues(dirPorts)...)
+		ports = append(ports, dirPorts...)
+	}
+
+	return append(issues, getPortIssues(path, contents, appConfig.HTTP.Port, ports)...)
+}
+
+func (v *Validator) validateAPIDir(apiDir string, appConfig *config.AppConfig) ([]Issue, []apiPort) {
+	files, err := v.file.ReadDir(apiDir)
+	if err != nil {
+		return []Issue{{File: apiDir, Message: err.Error()}}, nil
+	}
+
+	var issues []Issue
+	var ports []apiPort
+	for _, file := range files {
+		if !file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		if !config.IsAPIDir(file.Name()) {
+			groupIssues, groupPorts := v.validateAPIDir(filepath.Join(apiDir, file.Name()), appConfig)
+			issues = append(issues, groupIssues...)
+			ports = append(ports, groupPorts...)
+			continue
+		}
+
+		dir := filepath.Join(apiDir, file.Name())
+		path, err := config.FindAPIConfigFile(v.file, dir)
+		if err != nil {
+			issues = append(issues, Issue{File: dir, Message: err.Error()})
+			continue
+		}
+		if len(path) == 0 {
+			issues = append(issues, Issue{File: dir, Message: "no mock API configuration file"})
+			continue
+		}
+
+		apiIssues, port := v.validateAPI(file.Name(), dir, path, appConfig)
+		issues = append(issues, apiIssues...)
+		if port != nil {
+			ports = append(ports, *port)
+		}
+	}
+	return issues, ports
+}
+
+func (v *Validator) validateAPI(name, dir, path string, appConfig *config.AppConfig) ([]Issue, *apiPort) {
+	contents := v.readContents(path)
+	issues, ok := v.validateKeys(path, contents, make(map[string]bool))
+	if !ok {
+		return issues, nil
+	}
+
+	effectiveConfig, err := config.DecodeAPIConfigFile(v.file, path, &appConfig.Defaults)
+	if err != nil {
+		return append(issues, Issue{File: path, Message: err.Error()}), nil
+	}
+	apiConfig := *effectiveConfig
+	hubHTTP := appConfig.HTTP
+
+	if apiConfig.HTTP.Port < 0 {
+		issues = append(issues, Issue{File: path, Line: findKeyLine(contents, "http", "port"), Message: fmt.Sprintf("invalid port: %d", apiConfig.HTTP.Port)})
+	}
+	if apiConfig.HTTP.UseTLS {
+		httpConfig := apiConfig.HTTP
+		if len(httpConfig.CertFile) == 0 && len(httpConfig.KeyFile) == 0 {
+			httpConfig.CertFile, httpConfig.KeyFile = hubHTTP.CertFile, hubHTTP.KeyFile
+		}
+		issues = append(issues, v.validateTLSFiles(path, contents, httpConfig)...)
+	}
+
+	if !api.IsProtocol(apiConfig.Protocol) {
+		issues = append(issues, Issue{File: path, Line: findKeyLine(contents, "protocol"), Message: fmt.Sprintf("unknown protocol: %s", apiConfig.Protocol)})
+	}
+
+	if strings.EqualFold(apiConfig.Protocol, api.ProtocolGRPC) {
+		issues = append(issues, v.validateGRPCAPI(path, contents, dir, &apiConfig)...)
+	} else {
+		problems := api.FindEndpointProblems(&apiConfig)
+		for _, endpointName := range getEndpointNames(&apiConfig) {
+			line := findKeyLine(contents, "endpoints", endpointName)
+			issues = append(issues, v.validateEndpoint(path, contents, dir, endpointName, apiConfig.Endpoints[endpointName])...)
+			if problem, exists := problems[endpointName]; exists {
+				issues = append(issues, Issue{File: path, Line: line, Message: fmt.Sprintf("endpoint %s: %s", endpointName, problem)})
+			}
+		}
+	}
+
+	return issues, &apiPort{
+		name:   name,
+		file:   path,
+		line:   findKeyLine(contents, "http", "port"),
+		port:   apiConfig.HTTP.Port,
+		shared: apiConfig.HTTP.Shared,
+	}
+}
+
+// validateGRPCAPI checks the descriptor set of a gRPC mock API and the methods its endpoints answer.
+// Of two endpoints answering the same method, the one whose name sorts later is reported.
+func (v *Validator) validateGRPCAPI(path, contents, dir string, apiConfig *config.APIConfig) []Issue {
+	var issues []Issue
+	if apiConfig.HTTP.Shared {
+		issues = append(issues, Issue{File: path, Line: findKeyLine(contents, "http", "shared"), Message: "gRPC mock APIs cannot share a port"})
+	}
+
+	line := findKeyLine(contents, "descriptorSet")
+	if len(apiConfig.DescriptorSet) == 0 {
+		return append(issues, Issue{File: path, Line: findKeyLine(contents, "protocol"), Message: "gRPC mock API has no descriptor set"})
+	}
+	descriptorSetPath := filepath.Join(dir, apiConfig.DescriptorSet)
+	if err := v.validateFile(descriptorSetPath, false, false); err != nil {
+		return append(issues, Issue{File: path, Line: line, Message: err.Error()})
+	}
+	descriptorSet, err := v.readFile(descriptorSetPath)
+	if err != nil {
+		return append(issues, Issue{File: path, Line: line, Message: err.Error()})
+	}
+	files, err := api.LoadDescriptorSet(descriptorSet)
+	if err != nil {
+		return append(issues, Issue{File: path, Line: line, Message: err.Error()})
+	}
+
+	methods := make(map[string]string)
+	for _, endpointName := range getEndpointNames(apiConfig) {
+		endpoint := apiConfig.Endpoints[endpointName]
+		report := func(message string) {
+			issues = append(issues, Issue{File: path, Line: findKeyLine(contents, "endpoints", endpointName), Message: fmt.Sprintf("endpoint %s: %s", endpointName, message)})
+		}
+
+		var fixture []byte
+		if len(endpoint.File) > 0 {
+			filePath := filepath.Join(dir, endpoint.File)
+			if err := v.validateFile(filePath, false, false); err != nil {
+				report(err.Error())
+				continue
+			}
+			if fixture, err = v.readFile(filePath); err != nil {
+				report(err.Error())
+				continue
+			}
+			if endpoint.ExpandEnv {
+				fixture = []byte(config.ExpandEnv(string(fixture)))
+			}
+		}
+		for _, problem := range api.FindGRPCProblems(files, endpoint, fixture) {
+			report(problem)
+		}
+
+		method := strings.TrimPrefix(endpoint.Path, "/")
+		if existing, exists := methods[method]; exists {
+			report(fmt.Sprintf("answers the same gRPC method as endpoint %s (%s)", existing, method))
+			continue
+		}
+		methods[method] = endpointName
+	}
+	return issues
+}
+
+// validateKeys reports the unknown keys of a mock API configuration file and of the files it
+// includes, returning false if the file cannot be decoded.
+func (v *Validator) validateKeys(path, contents string, visited map[string]bool) ([]Issue, bool) {
+	visited[filepath.Clean(path)] = true
+
+	var apiConfig config.APIConfig
+	md, err := config.DecodeFileStrictly(v.file, path, &apiConfig)
+	if err != nil {
+		return []Issue{{File: path, Message: err.Error()}}, false
+	}
+
+	issues := getUndecodedIssues(path, contents, md)
+	for _, include := range apiConfig.Include {
+		includePath := include
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(filepath.Dir(path), includePath)
+		}
+		if visited[filepath.Clean(includePath)] {
+			continue
+		}
+		if _, err := v.file.Stat(includePath); err != nil {
+			issues = append(issues, Issue{File: path, Line: findKeyLine(contents, "include"), Message: fmt.Sprintf("included file not found: %s", includePath)})
+			continue
+		}
+
+		includeIssues, _ := v.validateKeys(includePath, v.readContents(includePath), visited)
+		issues = append(issues, includeIssues...)
+	}
+	return issues, true
+}
+
+func (v *Validator) validateEndpoint(path, contents, dir, name string, endpoint config.Endpoint) []Issue {
+	var issues []Issue
+	report := func(key, message string) {
+		line := findKeyLine(contents, "endpoints", name, key)
+		if line == 0 {
+			line = findKeyLine(contents, "endpoints", name)
+		}
+		issues = append(issues, Issue{File: path, Line: line, Message: fmt.Sprintf("endpoint %s: %s", name, message)})
+	}
+
+	if len(endpoint.Method) == 0 {
+		report("method", "no HTTP method")
+	} else if !isHTTPMethod(endpoint.Method) {
+		report("method", fmt.Sprintf("invalid HTTP method: %s", endpoint.Method))
+	}
+
+	if !isStatusCode(endpoint.HTTPStatusCode) {
+		report("httpStatusCode", fmt.Sprintf("invalid HTTP status code: %d", endpoint.HTTPStatusCode))
+	}
+	if code := endpoint.RequestSchemaStatusCode; code != 0 && (code < 400 || code > 499) {
+		report("requestSchemaStatusCode", fmt.Sprintf("request schema status code must be a 4xx status code: %d", code))
+	}
+
+	isSSE := strings.EqualFold(endpoint.Type, api.EndpointTypeSSE)
+	if !api.IsEndpointType(endpoint.Type) {
+		report("type", fmt.Sprintf("unknown endpoint type: %s", endpoint.Type))
+	}
+	if isSSE {
+		for _, problem := range api.FindSSEProblems(endpoint) {
+			report("sse", problem)
+		}
+	}
+	if strings.EqualFold(endpoint.Type, api.EndpointTypeWebSocket) {
+		for _, problem := range api.FindWebSocketProblems(endpoint) {
+			report("webSocket", problem)
+		}
+	}
+
+	if strings.EqualFold(endpoint.Type, api.EndpointTypeGraphQL) {
+		for _, problem := range v.findGraphQLProblems(dir, endpoint) {
+			report("graphQL", problem)
+		}
+	}
+
+	if !api.IsCompressionMode(endpoint.Compression) {
+		report("compression", fmt.Sprintf("invalid compression: %s", endpoint.Compression))
+	}
+
+	if endpoint.EnforceValidJSON && endpoint.EnforceValidXML {
+		report("enforceValidXML", "both enforceValidJSON and enforceValidXML are set; enforceValidXML is ignored")
+	}
+	enforceValidXML := endpoint.EnforceValidXML && !endpoint.EnforceValidJSON
+
+	if len(endpoint.File) > 0 {
+		if err := v.validateFile(filepath.Join(dir, endpoint.File), endpoint.EnforceValidJSON || isSSE, endpoint.ExpandEnv); err != nil {
+			report("file", err.Error())
+		} else if err := v.validateXMLFile(filepath.Join(dir, endpoint.File), enforceValidXML, endpoint.ExpandEnv); err != nil {
+			report("file", err.Error())
+		}
+	} else if endpoint.JSON == nil && endpoint.EnforceValidJSON && len(endpoint.Body) > 0 && json.ValidateJSON([]byte(endpoint.Body)) != nil {
+		report("body", "body is not valid JSON")
+	} else if endpoint.JSON == nil && enforceValidXML && len(endpoint.Body) > 0 && xml.ValidateXML([]byte(endpoint.Body)) != nil {
+		report("body", "body is not valid XML")
+	}
+	if message := getInlineBodyConflict(endpoint.File, endpoint.Body, endpoint.JSON); len(message) > 0 {
+		report("body", message)
+	}
+
+	if len(endpoint.RequestSchema) > 0 {
+		if err := v.validateFile(filepath.Join(dir, endpoint.RequestSchema), true, false); err != nil {
+			report("requestSchema", err.Error())
+		}
+	}
+	if len(endpoint.ResponseSchema) > 0 {
+		if err := v.validateFile(filepath.Join(dir, endpoint.ResponseSchema), true, false); err != nil {
+			report("responseSchema", err.Error())
+		}
+	}
+
+	for i, response := range endpoint.Sequence {
+		if !isStatusCode(response.HTTPStatusCode) {
+			report("sequence", fmt.Sprintf("response %d: invalid HTTP status code: %d", i+1, response.HTTPStatusCode))
+		}
+		if len(response.File) > 0 {
+			if err := v.validateFile(filepath.Join(dir, response.File), endpoint.EnforceValidJSON, endpoint.ExpandEnv); err != nil {
+				report("sequence", fmt.Sprintf("response %d: %v", i+1, err))
+			}
+		} else if response.JSON == nil && endpoint.EnforceValidJSON && len(response.Body) > 0 && json.ValidateJSON([]byte(response.Body)) != nil {
+			report("sequence", fmt.Sprintf("response %d: body is not valid JSON", i+1))
+		}
+		if message := getInlineBodyConflict(response.File, response.Body, response.JSON); len(message) > 0 {
+			report("sequence", fmt.Sprintf("response %d: %s", i+1, message))
+		}
+	}
+
+	return issues
+}
+
+func (v *Validator) validateFile(path string, enforceValidJSON, expandEnv bool) error {
+	if _, err := v.file.Stat(path); err != nil {
+		return fmt.Errorf("file not found: %s", path)
+	}
+	if !enforceValidJSON {
+		return nil
+	}
+
+	file, err := v.file.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	bytes, err := v.file.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	if expandEnv {
+		bytes = []byte(config.ExpandEnv(string(bytes)))
+	}
+	if json.ValidateJSON(bytes) != nil {
+		return fmt.Errorf("file is not valid JSON: %s", path)
+	}
+	return nil
+}
+
+func (v *Validator) validateXMLFile(path string, enforceValidXML, expandEnv bool) error {
+	if !enforceValidXML {
+		return nil
+	}
+
+	bytes, err := v.readFile(path)
+	if err != nil {
+		return err
+	}
+	if expandEnv {
+		bytes = []byte(config.ExpandEnv(string(bytes)))
+	}
+	if xml.ValidateXML(bytes) != nil {
+		return fmt.Errorf("file is not valid XML: %s", path)
+	}
+	return nil
+}
+
+func (v *Validator) validateTLSFiles(path, contents string, httpConfig config.HTTP) []Issue {
+	var issues []Issue
+	if len(httpConfig.CertFile) == 0 {
+		issues = append(issues, Issue{File: path, Line: findKeyLine(contents, "http", "useTLS"), Message: "TLS enabled without certFile"})
+	} else if _, err := v.file.Stat(httpConfig.CertFile); err != nil {
+		issues = append(issues, Issue{File: path, Line: findKeyLine(contents, "http", "certFile"), Message: fmt.Sprintf("file not found: %s", httpConfig.CertFile)})
+	}
+
+	if len(httpConfig.KeyFile) == 0 {
+		issues = append(issues, Issue{File: path, Line: findKeyLine(contents, "http", "useTLS"), Message: "TLS enabled without keyFile"})
+	} else if _, err := v.file.Stat(httpConfig.KeyFile); err != nil {
+		issues = append(issues, Issue{File: path, Line: findKeyLine(contents, "http", "keyFile"), Message: fmt.Sprintf("file not found: %s", httpConfig.KeyFile)})
+	}
+	return issues
+}
+
+// readContents returns the contents of a TOML file, in which lines of keys are looked up, or an
+// empty string for a file in any other format.
+func (v *Validator) readContents(path string) string {
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" || ext == ".json" {
+		return ""
+	}
+
+	bytes, err := v.readFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(bytes)
+}
+
+func (v *Validator) readFile(path string) ([]byte, error) {
+	file, err := v.file.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return v.file.ReadAll(file)
+}
+
+// findGraphQLProblems reports a GraphQL schema that is missing or does not parse, and fixtures that
+// are not valid JSON.
+func (v *Validator) findGraphQLProblems(dir string, endpoint config.Endpoint) []string {
+	var problems []string
+	if len(endpoint.GraphQL.Schema) == 0 {
+		problems = append(problems, "no GraphQL schema file")
+	} else {
+		path := filepath.Join(dir, endpoint.GraphQL.Schema)
+		if err := v.validateFile(path, false, false); err != nil {
+			problems = append(problems, err.Error())
+		} else if _, err := graphql.LoadSchema(path, v.readContents(path)); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid GraphQL schema: %v", err))
+		}
+	}
+
+	if len(endpoint.GraphQL.Fixtures) > 0 {
+		if err := v.validateFile(filepath.Join(dir, endpoint.GraphQL.Fixtures), true, endpoint.ExpandEnv); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	return problems
+}
+
+func getEndpointNames(apiConfig *config.APIConfig) []string {
+	names := make([]string, 0, len(apiConfig.Endpoints))
+	for endpointName := range apiConfig.Endpoints {
+		names = append(names, endpointName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func getUndecodedIssues(path, contents string, md toml.MetaData) []Issue {
+	var issues []Issue
+	for _, key := range md.Undecoded() {
+		issues = append(issues, Issue{File: path, Line: findKeyLine(contents, key...), Message: fmt.Sprintf("unknown key: %s", key.String())})
+	}
+	return issues
+}
+
+// getNameIssues reports mock APIs in the same mock API directory, including its grouping directories,
+// that have the same name as another, since only the first is loaded.
+func getNameIssues(ports []apiPort) []Issue {
+	var issues []Issue
+	files := make(map[string]string)
+	for _, port := range ports {
+		if file, exists := files[port.name]; exists {
+			issues = append(issues, Issue{File: port.file, Message: fmt.Sprintf("mock API %s is not loaded because %s has the same name", port.name, file)})
+			continue
+		}
+		files[port.name] = port.file
+	}
+	return issues
+}
+
+func getPortIssues(hubPath, hubContents string, hubPort config.Port, ports []apiPort) []Issue {
+	var issues []Issue
+	for i, port := range ports {
+		if port.port == 0 {
+			continue
+		}
+
+		if port.port == hubPort {
+			issues = append(issues, Issue{File: port.file, Line: port.line, Message: fmt.Sprintf("port %d is used by the hub (%s)", port.port, hubPath)})
+			continue
+		}
+
+		for _, other := range ports[:i] {
+			if other.port != port.port || (other.shared && port.shared) {
+				continue
+			}
+			issues = append(issues, Issue{File: port.file, Line: port.line, Message: fmt.Sprintf("port %d is also used by mock API %s (%s)", port.port, other.name, other.file)})
+			break
+		}
+	}
+	return issues
+}
+
+// getInlineBodyConflict describes which of a response's bodies is ignored, given that a file takes
+// precedence over a json table, which takes precedence over a body string.
+func getInlineBodyConflict(file, body string, jsonBody interface{}) string {
+	switch {
+	case len(file) > 0 && jsonBody != nil:
+		return "both file and json are set; json is ignored"
+	case len(file) > 0 && len(body) > 0:
+		return "both file and body are set; body is ignored"
+	case jsonBody != nil && len(body) > 0:
+		return "both json and body are set; body is ignored"
+	}
+	return ""
+}
+
+func isHTTPMethod(method string) bool {
+	for _, httpMethod := range httpMethods {
+		if strings.EqualFold(method, httpMethod) {
+			return true
+		}
+	}
+	return false
+}
+
+func isStatusCode(code int) bool {
+	return code == 0 || len(http.StatusText(code)) > 0
+}
+
+// findKeyLine returns the number of the line on which the key provided, given as its path of table
+// names and key names, is defined, or 0 if it cannot be found. Keys are compared case-insensitively,
+// as the decoder matches them to fields.
+func findKeyLine(contents string, key ...string) int {
+	var table []string
+	for i, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			header := strings.Trim(line[:strings.LastIndex(line, "]")+1], "[]")
+			table = splitKey(header)
+			if keyEquals(table, key) {
+				return i + 1
+			}
+			continue
+		}
+
+		equals := strings.Index(line, "=")
+		if equals < 0 {
+			continue
+		}
+		fullKey := append(append([]string{}, table...), splitKey(line[:equals])...)
+		if keyEquals(fullKey, key) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func splitKey(key string) []string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return parts
+}
+
+func keyEquals(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !strings.EqualFold(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/wcsanders1/MockApiHub/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	invalidAPIConfig = "testdata/invalid/mockApis/ordersApi/ordersApi.toml"
+	grpcAPIConfig    = "testdata/grpc/mockApis/ordersApi/ordersApi.toml"
+)
+
+func getIssueStrings(issues []Issue) []string {
+	var result []string
+	for _, issue := range issues {
+		result = append(result, issue.String())
+	}
+	return result
+}
+
+func TestValidate_ReturnsNoIssues_WhenConfigurationValid(t *testing.T) {
+	validator := NewValidator()
+
+	result := validator.Validate(config.Options{AppConfigPath: "testdata/valid/app_config.toml"})
+
+	assert.Empty(t, result)
+}
+
+func TestValidate_ReturnsIssues_WhenConfigurationInvalid(t *testing.T) {
+	validator := NewValidator()
+
+	result := getIssueStrings(validator.Validate(config.Options{AppConfigPath: "testdata/invalid/app_config.toml"}))
+
+	assert := assert.New(t)
+	assert.Contains(result, "testdata/invalid/app_config.toml:2: unknown key: portsFlie")
+	assert.Contains(result, invalidAPIConfig+":10: unknown key: endpoints.getOrders.fiel")
+	assert.Contains(result, invalidAPIConfig+":11: endpoint getOrders: invalid HTTP method: GTE")
+	assert.Contains(result, invalidAPIConfig+":12: endpoint getOrders: invalid HTTP status code: 999")
+	assert.Contains(result, invalidAPIConfig+":17: endpoint getOrdersAgain: invalid HTTP method: GTE")
+	assert.Contains(result, invalidAPIConfig+":16: endpoint getOrdersAgain: file not found: testdata/invalid/mockApis/ordersApi/missing.json")
+	assert.Contains(result, invalidAPIConfig+":14: endpoint getOrdersAgain: handles the same requests as endpoint getOrders (GTE ordersapi/orders)")
+	assert.Contains(result, invalidAPIConfig+":21: endpoint getOrder: file is not valid JSON: testdata/invalid/mockApis/ordersApi/order.json")
+	assert.Contains(result, invalidAPIConfig+":24: endpoint getOrder: invalid compression: zip")
+	assert.Contains(result, invalidAPIConfig+":31: endpoint getOrderEvents: invalid keep-alive interval: time: invalid duration \"often\"")
+	assert.Contains(result, invalidAPIConfig+":31: endpoint getOrderEvents: server-sent events endpoint has neither a file nor a template")
+	assert.Contains(result, invalidAPIConfig+":34: endpoint getOrderFeed: rule 1: invalid regex: error parsing regexp: missing closing ): `(`")
+	assert.Contains(result, invalidAPIConfig+":42: endpoint getOrderGraph: no GraphQL schema file")
+	assert.Contains(result, invalidAPIConfig+":51: endpoint getOrderSoap: body is not valid XML")
+	assert.Contains(result, invalidAPIConfig+":47: endpoint getOrderSoap: invalid XPath expression //GetOrder[: expression must evaluate to a node-set")
+	assert.Contains(result, invalidAPIConfig+":4: port 5000 is used by the hub (testdata/invalid/app_config.toml)")
+	assert.Contains(result, "testdata/invalid/mockApis/teachersApi/teachersApi.toml:2: port 5001 is also used by mock API studentsApi (testdata/invalid/mockApis/studentsApi/studentsApi.toml)")
+	assert.Equal(17, len(result))
+}
+
+func TestValidate_UsesAPIDirsOption_WhenProvided(t *testing.T) {
+	validator := NewValidator()
+
+	result := validator.Validate(config.Options{
+		AppConfigPath: "testdata/invalid/app_config.toml",
+		APIDirs:       "testdata/valid/mockApis",
+	})
+
+	assert.Equal(t, []string{"testdata/invalid/app_config.toml:2: unknown key: portsFlie"}, getIssueStrings(result))
+}
+
+func TestValidate_ReturnsIssue_WhenAppConfigMissing(t *testing.T) {
+	validator := NewValidator()
+
+	result := validator.Validate(config.Options{AppConfigPath: "testdata/missing.toml"})
+
+	assert := assert.New(t)
+	assert.Equal(1, len(result))
+	assert.Equal("testdata/missing.toml", result[0].File)
+}
+
+func TestGetPortIssues_ReturnsNoIssues_WhenSharedAPIsSharePort(t *testing.T) {
+	ports := []apiPort{
+		{name: "customersApi", file: "customersApi.toml", port: 5001, shared: true},
+		{name: "ordersApi", file: "ordersApi.toml", port: 5001, shared: true},
+		{name: "studentsApi", file: "studentsApi.toml", port: 0},
+		{name: "teachersApi", file: "teachersApi.toml", port: 0},
+	}
+
+	result := getPortIssues("app_config.toml", "", 5000, ports)
+
+	assert.Empty(t, result)
+}
+
+func TestGetPortIssues_ReturnsIssue_WhenUnsharedAPIUsesSharedPort(t *testing.T) {
+	ports := []apiPort{
+		{name: "customersApi", file: "customersApi.toml", port: 5001, shared: true},
+		{name: "ordersApi", file: "ordersApi.toml", line: 3, port: 5001},
+	}
+
+	result := getIssueStrings(getPortIssues("app_config.toml", "", 5000, ports))
+
+	assert.Equal(t, []string{"ordersApi.toml:3: port 5001 is also used by mock API customersApi (customersApi.toml)"}, result)
+}
+
+func TestFindKeyLine_ReturnsLine_WhenKeyInArrayOfTables(t *testing.T) {
+	contents := "[endpoints.getOrders]\nmethod = \"GET\"\n\n[[endpoints.getOrders.sequence]]\nfile = \"a.json\"\n\n[[endpoints.getOrders.Sequence]]\nFiel = \"b.json\"\n"
+
+	result := findKeyLine(contents, "endpoints", "getOrders", "sequence", "fiel")
+
+	assert.Equal(t, 8, result)
+}
+
+func TestFindKeyLine_ReturnsZero_WhenKeyNotFound(t *testing.T) {
+	result := findKeyLine("[http]\nport = 5000\n", "http", "useTLS")
+
+	assert.Equal(t, 0, result)
+}
+
+func TestValidate_ValidatesNestedAPIs_WhenDirectoryGroupsAPIs(t *testing.T) {
+	validator := NewValidator()
+
+	result := getIssueStrings(validator.Validate(config.Options{AppConfigPath: "testdata/nested/app_config.toml"}))
+
+	assert.Equal(t, []string{
+		"testdata/nested/mockApis/ambiguousApi: more than one mock API configuration file in testdata/nested/mockApis/ambiguousApi: ambiguousApi.json, ambiguousApi.toml",
+		"testdata/nested/mockApis/ordersApi/ordersApi.toml: mock API ordersApi is not loaded because testdata/nested/mockApis/billing/ordersApi/ordersApi.toml has the same name",
+	}, result)
+}
+
+func TestGetInlineBodyConflict_DescribesIgnoredBody_WhenSeveralBodiesSet(t *testing.T) {
+	jsonBody := map[string]interface{}{"id": 1}
+
+	assert := assert.New(t)
+	assert.Equal("both file and json are set; json is ignored", getInlineBodyConflict("a.json", "", jsonBody))
+	assert.Equal("both file and body are set; body is ignored", getInlineBodyConflict("a.json", "b", nil))
+	assert.Equal("both json and body are set; body is ignored", getInlineBodyConflict("", "b", jsonBody))
+	assert.Empty(getInlineBodyConflict("", "b", nil))
+}
+
+func TestValidate_ValidatesGRPCMethods_WhenAPIUsesGRPC(t *testing.T) {
+	validator := NewValidator()
+
+	result := getIssueStrings(validator.Validate(config.Options{AppConfigPath: "testdata/grpc/app_config.toml"}))
+
+	assert := assert.New(t)
+	assert.Contains(result, grpcAPIConfig+":23: endpoint cancelOrder: method CancelOrder not found in service orders.OrderService")
+	assert.Contains(result, grpcAPIConfig+":13: endpoint getOrderAgain: answers the same gRPC method as endpoint getOrder (orders.OrderService/GetOrder)")
+	assert.Contains(result, grpcAPIConfig+":16: endpoint watchOrder: invalid gRPC status: LOST")
+	assert.Contains(result[3], grpcAPIConfig+":16: endpoint watchOrder: fixture of server-streaming method orders.OrderService.WatchOrder is not a JSON array")
+	assert.Equal(4, len(result))
+}
@@ -15,6 +15,8 @@ type (
 		ReadDir(dir string) ([]os.FileInfo, error)
 		DecodeFile(file string, v interface{}) (toml.MetaData, error)
 		Stat(file string) (os.FileInfo, error)
+		WriteFile(file string, data []byte, perm os.FileMode) error
+		MkdirAll(dir string, perm os.FileMode) error
 	}
 
 	// FileOps offers a real implementation of IFileOpc
@@ -45,3 +47,13 @@ func (ops *FileOps) DecodeFile(file string, v interface{}) (toml.MetaData, error
 func (ops *FileOps) Stat(file string) (os.FileInfo, error) {
 	return os.Stat(file)
 }
+
+// WriteFile writes data to the named file, creating it if necessary
+func (ops *FileOps) WriteFile(file string, data []byte, perm os.FileMode) error {
+	return ioutil.WriteFile(file, data, perm)
+}
+
+// MkdirAll creates a directory, along with any necessary parents
+func (ops *FileOps) MkdirAll(dir string, perm os.FileMode) error {
+	return os.MkdirAll(dir, perm)
+}
@@ -41,3 +41,15 @@ func (ops *FakeFileOps) Stat(file string) (os.FileInfo, error) {
 	args := ops.Called(file)
 	return args.Get(0).(os.FileInfo), args.Error(1)
 }
+
+// WriteFile is a fake implementation of IFileOps.WriteFile()
+func (ops *FakeFileOps) WriteFile(file string, data []byte, perm os.FileMode) error {
+	args := ops.Called(file, data, perm)
+	return args.Error(0)
+}
+
+// MkdirAll is a fake implementation of IFileOps.MkdirAll()
+func (ops *FakeFileOps) MkdirAll(dir string, perm os.FileMode) error {
+	args := ops.Called(dir, perm)
+	return args.Error(0)
+}
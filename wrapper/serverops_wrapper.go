@@ -3,6 +3,7 @@ package wrapper
 
 import (
 	"context"
+	"net"
 	"net/http"
 )
 
@@ -10,19 +11,21 @@ type (
 	// IServerOps contains basic server operations
 	IServerOps interface {
 		Shutdown(context.Context) error
+		Listen() (int, error)
 		ListenAndServe() error
 		ListenAndServeTLS(string, string) error
 	}
 
 	// ServerOps provides a real implementation of IServerOps
 	ServerOps struct {
-		server *http.Server
+		server   *http.Server
+		listener net.Listener
 	}
 )
 
 // NewServerOps returns a pointer to a new ServerOps
 func NewServerOps(server *http.Server) *ServerOps {
-	return &ServerOps{server}
+	return &ServerOps{server: server}
 }
 
 // Shutdown shuts down the server
@@ -30,12 +33,30 @@ func (ops *ServerOps) Shutdown(ctx context.Context) error {
 	return ops.server.Shutdown(ctx)
 }
 
-// ListenAndServe starts the server
+// Listen binds the server's address without serving and returns the port bound, which is useful
+// when the address does not specify a port
+func (ops *ServerOps) Listen() (int, error) {
+	listener, err := net.Listen("tcp", ops.server.Addr)
+	if err != nil {
+		return 0, err
+	}
+
+	ops.listener = listener
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// ListenAndServe starts the server, using the address bound by Listen if it has been called
 func (ops *ServerOps) ListenAndServe() error {
+	if ops.listener != nil {
+		return ops.server.Serve(ops.listener)
+	}
 	return ops.server.ListenAndServe()
 }
 
-// ListenAndServeTLS starts the server using TLS
+// ListenAndServeTLS starts the server using TLS, using the address bound by Listen if it has been called
 func (ops *ServerOps) ListenAndServeTLS(certFile, keyFile string) error {
+	if ops.listener != nil {
+		return ops.server.ServeTLS(ops.listener, certFile, keyFile)
+	}
 	return ops.server.ListenAndServeTLS(certFile, keyFile)
 }
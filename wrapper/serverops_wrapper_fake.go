@@ -29,6 +29,12 @@ func (ops *FakeServerOps) Shutdown(ctx context.Context) error {
 	return args.Error(0)
 }
 
+// Listen is a fake implementation of IServerOps.Listen()
+func (ops *FakeServerOps) Listen() (int, error) {
+	args := ops.Called()
+	return args.Int(0), args.Error(1)
+}
+
 // ListenAndServe is a fake implementation is IServerOps.ListenAndServe()
 func (ops *FakeServerOps) ListenAndServe() error {
 	args := ops.Called()
@@ -0,0 +1,47 @@
+//Package xml provides utility functions for operations with XML.
+package xml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+)
+
+// ValidateXML returns an error if bytes are not a well-formed XML document.
+func ValidateXML(contents []byte) error {
+	if !isValidXML(contents) {
+		return errors.New("invalid XML")
+	}
+	return nil
+}
+
+// isValidXML reports whether contents hold exactly one root element, with nothing but whitespace,
+// comments and processing instructions around it.
+func isValidXML(contents []byte) bool {
+	decoder := xml.NewDecoder(bytes.NewReader(contents))
+	roots, depth := 0, 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return roots == 1 && depth == 0
+		}
+		if err != nil {
+			return false
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				roots++
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 && len(bytes.TrimSpace(t)) > 0 {
+				return false
+			}
+		}
+	}
+}
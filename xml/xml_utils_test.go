@@ -0,0 +1,30 @@
+package xml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var goodXML = []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<!-- an invoice -->
+<invoice id="7">
+	<total>9.50</total>
+</invoice>
+`)
+
+func TestValidateXML_ReturnsNil_WhenXMLWellFormed(t *testing.T) {
+	assert.Nil(t, ValidateXML(goodXML))
+}
+
+func TestValidateXML_ReturnsError_WhenElementNotClosed(t *testing.T) {
+	assert.Error(t, ValidateXML([]byte("<invoice><total>9.50</invoice>")))
+}
+
+func TestValidateXML_ReturnsError_WhenNotOneRootElement(t *testing.T) {
+	assert := assert.New(t)
+	assert.Error(ValidateXML([]byte("<a/><b/>")))
+	assert.Error(ValidateXML([]byte("")))
+	assert.Error(ValidateXML([]byte(`{"invoice": 7}`)))
+	assert.Error(ValidateXML([]byte("<a/> trailing")))
+}
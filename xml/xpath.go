@@ -0,0 +1,61 @@
+package xml
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+)
+
+// localNameNavigator hides the prefixes of names, so that the names in XPath expressions match
+// elements and attributes by their local names, whatever their namespaces.
+type localNameNavigator struct {
+	*xmlquery.NodeNavigator
+}
+
+// Parse parses an XML document so that XPath expressions can be evaluated against it.
+func Parse(contents []byte) (*xmlquery.Node, error) {
+	return xmlquery.Parse(bytes.NewReader(contents))
+}
+
+// CompilePath compiles an XPath expression, such as //GetInvoice/invoiceId or count(//line) > 2.
+// Namespace prefixes are ignored, so names in expressions have none.
+func CompilePath(expression string) (*xpath.Expr, error) {
+	return xpath.Compile(expression)
+}
+
+// Lookup evaluates a compiled XPath expression against a parsed XML document. It returns the text of
+// the first node the expression selects, or the expression's value if it is a string, number or
+// boolean, and whether the expression has a value; an expression that selects no nodes has none.
+func Lookup(document *xmlquery.Node, expr *xpath.Expr) (string, bool) {
+	switch value := expr.Evaluate(localNameNavigator{xmlquery.CreateXPathNavigator(document)}).(type) {
+	case *xpath.NodeIterator:
+		if !value.MoveNext() {
+			return "", false
+		}
+		return value.Current().Value(), true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(value), true
+	case string:
+		return value, true
+	}
+	return "", false
+}
+
+func (n localNameNavigator) Prefix() string {
+	return ""
+}
+
+func (n localNameNavigator) Copy() xpath.NodeNavigator {
+	return localNameNavigator{n.NodeNavigator.Copy().(*xmlquery.NodeNavigator)}
+}
+
+func (n localNameNavigator) MoveTo(other xpath.NodeNavigator) bool {
+	if navigator, ok := other.(localNameNavigator); ok {
+		return n.NodeNavigator.MoveTo(navigator.NodeNavigator)
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package xml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var envelope = []byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:b="urn:billing">
+	<soap:Body>
+		<b:GetInvoice>
+			<b:invoiceId>42</b:invoiceId>
+			<b:line>a</b:line>
+			<b:line>b</b:line>
+		</b:GetInvoice>
+	</soap:Body>
+</soap:Envelope>`)
+
+func lookup(t *testing.T, expression string) (string, bool) {
+	document, err := Parse(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expr, err := CompilePath(expression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Lookup(document, expr)
+}
+
+func TestLookup_ReturnsText_WhenExpressionSelectsNodes(t *testing.T) {
+	assert := assert.New(t)
+	for _, expression := range []string{"//invoiceId", "/Envelope/Body/GetInvoice/invoiceId", "//*[local-name()='invoiceId']"} {
+		result, found := lookup(t, expression)
+		assert.True(found, expression)
+		assert.Equal("42", result, expression)
+	}
+}
+
+func TestLookup_ReturnsValue_WhenExpressionNotNodeSet(t *testing.T) {
+	count, _ := lookup(t, "count(//line)")
+	exists, _ := lookup(t, "boolean(//GetInvoice)")
+	name, _ := lookup(t, "local-name(/*/*/*)")
+
+	assert := assert.New(t)
+	assert.Equal("2", count)
+	assert.Equal("true", exists)
+	assert.Equal("GetInvoice", name)
+}
+
+func TestLookup_ReturnsNotFound_WhenExpressionSelectsNothing(t *testing.T) {
+	result, found := lookup(t, "//GetBalance")
+
+	assert := assert.New(t)
+	assert.False(found)
+	assert.Empty(result)
+}
+
+func TestLookup_ReturnsNotFound_WhenExpressionHasPrefix(t *testing.T) {
+	_, found := lookup(t, "//b:invoiceId")
+
+	assert.False(t, found)
+}
+
+func TestCompilePath_ReturnsError_WhenExpressionInvalid(t *testing.T) {
+	_, err := CompilePath("//GetInvoice[")
+
+	assert.Error(t, err)
+}